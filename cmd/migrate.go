@@ -14,7 +14,7 @@ func main() {
 	}
 
 	// Initialize database connection
-	db, err := internal.InitDB()
+	db, err := internal.InitMigrationDB()
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/config"
 	"github.com/dedenfarhanhub/blog-service/internal"
 	"github.com/dedenfarhanhub/blog-service/internal/services"
 	"github.com/joho/godotenv"
 	"log"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 )
 
 // @title           Blog API GO
@@ -44,9 +53,80 @@ func main() {
 	redisClient := internal.InitRedis()
 	redisService := services.NewRedisService(redisClient)
 
-	r := internal.InitRouter(db, redisService)
+	healthService, err := internal.InitHealth(db, redisService)
+	if err != nil {
+		log.Fatalf("Failed to initialize health checks: %v", err)
+	}
+
+	r := internal.InitRouter(db, redisService, healthService)
+	cfg := config.LoadConfig()
+
+	// Publish scheduled posts and collect unused tags in the background
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	var schedulers sync.WaitGroup
+	schedulers.Add(2)
+	go func() {
+		defer schedulers.Done()
+		internal.StartPostScheduler(schedulerCtx, db, redisService, cfg.PostSchedulerInterval)
+	}()
+	go func() {
+		defer schedulers.Done()
+		internal.StartTagCollector(schedulerCtx, db, redisService, cfg.TagCollectorInterval)
+	}()
 
-	if err := r.Run(":9000"); err != nil {
-		log.Fatal(err)
+	// Serve until SIGINT or SIGTERM, or until the listener fails
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	server := internal.NewHTTPServer(r, cfg)
+	metricsServer := internal.NewMetricsServer(cfg)
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- internal.ServeHTTP(server, cfg)
+	}()
+	go func() {
+		if err := metricsServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- fmt.Errorf("metrics: %w", err)
+		}
+	}()
+	log.Printf("server: listening on %s, metrics on %s", cfg.HTTPAddr, cfg.MetricsAddr)
+
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			log.Fatalf("server: %v", err)
+		}
+	case <-signalCtx.Done():
+	}
+	stopSignals()
+
+	// Report unready first and keep serving a while, so load balancers stop sending new requests
+	healthService.MarkShuttingDown()
+	if cfg.ShutdownDelay > 0 {
+		log.Printf("server: unready, shutting down in %s", cfg.ShutdownDelay)
+		time.Sleep(cfg.ShutdownDelay)
+	}
+	log.Printf("server: shutting down, waiting up to %s for in-flight requests", cfg.ShutdownTimeout)
+
+	// Stop accepting connections and let the in-flight requests finish within the grace period
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+	stopScheduler()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("server: grace period over, closing the remaining connections: %v", err)
+		_ = server.Close()
+	}
+	_ = metricsServer.Close()
+	schedulers.Wait()
+
+	// Nothing uses the database or Redis anymore
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			log.Printf("server: closing the database: %v", err)
+		}
+	}
+	if err := redisClient.Close(); err != nil {
+		log.Printf("server: closing redis: %v", err)
 	}
+	log.Printf("server: stopped")
 }
@@ -2,30 +2,258 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// RateLimit allows Limit requests per Period
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
 // Config holds the configuration settings for the application.
 type Config struct {
-	DBUser     string
-	DBPassword string
-	DBHost     string
-	DBPort     string
-	DBName     string
-	JWTSecret  string
-	RedisHost  string
-	RedisPort  string
+	DBUser          string
+	DBPassword      string
+	DBHost          string
+	DBPort          string
+	DBName          string
+	JWTSecret       string
+	RedisHost       string
+	RedisPort       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// JWTPrivateKeyFile is a PEM RSA or Ed25519 key signing access tokens with RS256 or EdDSA instead of
+	// HS256 and JWTSecret. JWTPublicKeyFiles are extra verification keys, kept while rotating keys.
+	JWTPrivateKeyFile string
+	JWTPublicKeyFiles []string
+	// JWTKeyID overrides the kid of the signing key, which defaults to the key's thumbprint
+	JWTKeyID string
+	// JWTIssuer is set as iss; tokens are issued for the first JWTAudience and accepted for any of them
+	JWTIssuer   string
+	JWTAudience []string
+	// JWTClockSkew is the leeway allowed on exp, nbf and iat for clocks drifting between servers
+	JWTClockSkew time.Duration
+	// PostSchedulerInterval is how often scheduled posts are checked for publication
+	PostSchedulerInterval time.Duration
+	// CommentMaxDepth is the deepest reply level allowed, top-level comments being depth 0
+	CommentMaxDepth int
+	// CommentModerationPolicy is the global policy (open, moderated, anonymous) for posts that do not set their own
+	CommentModerationPolicy string
+	// SpamThreshold marks comments as spam, SpamReviewThreshold holds them for moderation
+	SpamThreshold         float64
+	SpamReviewThreshold   float64
+	SpamBlockedWords      []string
+	SpamMinSubmitInterval time.Duration
+	// TagCollectorInterval is how often tags no longer used by any post are deleted
+	TagCollectorInterval time.Duration
+	// SearchBackend is "mysql" (FULLTEXT indexes, the default) or "memory" (in-process inverted index)
+	SearchBackend string
+	// CursorSecret signs pagination cursors, defaulting to the JWT secret
+	CursorSecret string
+	// RateLimitDefault applies to every request per client IP; the others to their routes per user or IP
+	RateLimitDefault  RateLimit
+	RateLimitLogin    RateLimit
+	RateLimitRegister RateLimit
+	RateLimitComment  RateLimit
+	// RateLimitBackend is "redis" (shared by all replicas, the default) or "memory" (per process)
+	RateLimitBackend string
+	// LoginLockoutThreshold failed logins within LoginFailureWindow lock an account for LoginLockoutDuration;
+	// LoginIPThreshold failed logins from one IP within the window block that IP
+	LoginLockoutThreshold int
+	LoginLockoutDuration  time.Duration
+	LoginFailureWindow    time.Duration
+	LoginIPThreshold      int
+	// Mailer is "log" (write emails to MailSinkFile, or the log when empty; the default) or "smtp"
+	Mailer       string
+	MailFrom     string
+	MailSinkFile string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	// AppBaseURL prefixes the links mailed to users
+	AppBaseURL string
+	// PasswordResetTTL and EmailVerificationTTL are how long mailed tokens stay valid
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+	// VerifiedEmailActions are the actions that need a verified email (post:create, comment:create)
+	VerifiedEmailActions []string
+	// TrustedProxies are the proxies whose X-Forwarded-For header is trusted for the client IP
+	TrustedProxies []string
+	// RequestTimeout bounds each request, cancelling its MySQL and Redis calls when it runs out
+	RequestTimeout time.Duration
+	// HTTPAddr is the address the server listens on. The read and write timeouts bound a whole request
+	// on the connection, so HTTPWriteTimeout should stay above RequestTimeout.
+	HTTPAddr              string
+	HTTPReadHeaderTimeout time.Duration
+	HTTPReadTimeout       time.Duration
+	HTTPWriteTimeout      time.Duration
+	HTTPIdleTimeout       time.Duration
+	// ShutdownDelay is how long the server keeps serving while reporting unready before it stops accepting
+	// connections, so load balancers notice; ShutdownTimeout is how long in-flight requests then get to finish
+	ShutdownDelay   time.Duration
+	ShutdownTimeout time.Duration
+	// HealthCheckTimeout bounds each dependency check of the readiness endpoint
+	HealthCheckTimeout time.Duration
+	// MetricsAddr is the address /metrics is served on, apart from the API so it is not exposed to clients
+	MetricsAddr string
+	// TLSCertFile and TLSKeyFile are PEM files; when both are set the server speaks HTTPS only
+	TLSCertFile string
+	TLSKeyFile  string
 }
 
 // LoadConfig loads the configuration settings from environment variables.
 func LoadConfig() *Config {
 	return &Config{
-		DBUser:     os.Getenv("DB_USER"),
-		DBPassword: os.Getenv("DB_PASSWORD"),
-		DBHost:     os.Getenv("DB_HOST"),
-		DBPort:     os.Getenv("DB_PORT"),
-		DBName:     os.Getenv("DB_NAME"),
-		JWTSecret:  os.Getenv("JWT_SECRET"),
-		RedisHost:  os.Getenv("REDIS_HOST"),
-		RedisPort:  os.Getenv("REDIS_PORT"),
+		DBUser:          os.Getenv("DB_USER"),
+		DBPassword:      os.Getenv("DB_PASSWORD"),
+		DBHost:          os.Getenv("DB_HOST"),
+		DBPort:          os.Getenv("DB_PORT"),
+		DBName:          os.Getenv("DB_NAME"),
+		JWTSecret:       os.Getenv("JWT_SECRET"),
+		RedisHost:       os.Getenv("REDIS_HOST"),
+		RedisPort:       os.Getenv("REDIS_PORT"),
+		AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		JWTPrivateKeyFile: os.Getenv("JWT_PRIVATE_KEY_FILE"),
+		JWTPublicKeyFiles: getEnvList("JWT_PUBLIC_KEY_FILES"),
+		JWTKeyID:          os.Getenv("JWT_KEY_ID"),
+		JWTIssuer:         getEnv("JWT_ISSUER", "blog-service"),
+		JWTAudience:       getEnvListOrDefault("JWT_AUDIENCE", []string{"blog-service"}),
+		JWTClockSkew:      getEnvDuration("JWT_CLOCK_SKEW", 30*time.Second),
+
+		PostSchedulerInterval: getEnvDuration("POST_SCHEDULER_INTERVAL", time.Minute),
+		CommentMaxDepth:       getEnvInt("COMMENT_MAX_DEPTH", 5),
+
+		CommentModerationPolicy: getEnv("COMMENT_MODERATION_POLICY", "open"),
+
+		SpamThreshold:         getEnvFloat("SPAM_THRESHOLD", 0.9),
+		SpamReviewThreshold:   getEnvFloat("SPAM_REVIEW_THRESHOLD", 0.5),
+		SpamBlockedWords:      getEnvList("SPAM_BLOCKED_WORDS"),
+		SpamMinSubmitInterval: getEnvDuration("SPAM_MIN_SUBMIT_INTERVAL", 10*time.Second),
+
+		TagCollectorInterval: getEnvDuration("TAG_COLLECTOR_INTERVAL", time.Hour),
+
+		SearchBackend: getEnv("SEARCH_BACKEND", "mysql"),
+		CursorSecret:  getEnv("CURSOR_SECRET", os.Getenv("JWT_SECRET")),
+
+		RateLimitDefault:  getEnvRateLimit("RATE_LIMIT_DEFAULT", RateLimit{Limit: 300, Period: time.Minute}),
+		RateLimitLogin:    getEnvRateLimit("RATE_LIMIT_LOGIN", RateLimit{Limit: 10, Period: time.Minute}),
+		RateLimitRegister: getEnvRateLimit("RATE_LIMIT_REGISTER", RateLimit{Limit: 5, Period: time.Hour}),
+		RateLimitComment:  getEnvRateLimit("RATE_LIMIT_COMMENT", RateLimit{Limit: 10, Period: time.Minute}),
+		RateLimitBackend:  getEnv("RATE_LIMIT_BACKEND", "redis"),
+		TrustedProxies:    getEnvList("TRUSTED_PROXIES"),
+		RequestTimeout:    getEnvDuration("REQUEST_TIMEOUT", 15*time.Second),
+
+		HTTPAddr:              getEnv("HTTP_ADDR", ":8090"),
+		HTTPReadHeaderTimeout: getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		HTTPReadTimeout:       getEnvDuration("HTTP_READ_TIMEOUT", 15*time.Second),
+		HTTPWriteTimeout:      getEnvDuration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		HTTPIdleTimeout:       getEnvDuration("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownDelay:         getEnvDuration("SHUTDOWN_DELAY", 0),
+		ShutdownTimeout:       getEnvDuration("SHUTDOWN_TIMEOUT", 20*time.Second),
+		HealthCheckTimeout:    getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		MetricsAddr:           getEnv("METRICS_ADDR", ":9100"),
+		TLSCertFile:           os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:            os.Getenv("TLS_KEY_FILE"),
+
+		LoginLockoutThreshold: getEnvInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+		LoginLockoutDuration:  getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		LoginFailureWindow:    getEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		LoginIPThreshold:      getEnvInt("LOGIN_IP_THRESHOLD", 50),
+
+		Mailer:       getEnv("MAILER", "log"),
+		MailFrom:     getEnv("MAIL_FROM", "Blog <no-reply@localhost>"),
+		MailSinkFile: os.Getenv("MAIL_SINK_FILE"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		AppBaseURL:   getEnv("APP_BASE_URL", "http://localhost:8090"),
+
+		PasswordResetTTL:     getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+		EmailVerificationTTL: getEnvDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		VerifiedEmailActions: getEnvListOrDefault("VERIFIED_EMAIL_ACTIONS", []string{"post:create"}),
+	}
+}
+
+// getEnv reads a string from the environment, falling back to the default when unset
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvInt reads a non-negative integer from the environment, falling back to the default
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// getEnvFloat reads a non-negative number from the environment, falling back to the default
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// getEnvList reads a comma separated list from the environment, skipping empty items
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvListOrDefault reads a comma separated list from the environment, falling back to the default only
+// when the variable is unset, so it can be set empty
+func getEnvListOrDefault(key string, fallback []string) []string {
+	if _, found := os.LookupEnv(key); !found {
+		return fallback
+	}
+	return getEnvList(key)
+}
+
+// getEnvDuration reads a duration (e.g. "15m", "720h") from the environment, falling back to the default
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+	return duration
+}
+
+// getEnvRateLimit reads a rate limit written as "<limit>/<period>" (e.g. "10/1m") from the environment,
+// falling back to the default
+func getEnvRateLimit(key string, fallback RateLimit) RateLimit {
+	limit, period, found := strings.Cut(os.Getenv(key), "/")
+	if !found {
+		return fallback
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || duration <= 0 {
+		return fallback
 	}
+	return RateLimit{Limit: value, Period: duration}
 }
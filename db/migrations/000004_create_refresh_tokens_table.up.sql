@@ -0,0 +1,14 @@
+CREATE TABLE refresh_tokens
+(
+    id             INT AUTO_INCREMENT PRIMARY KEY,
+    user_id        INT          NOT NULL,
+    family_id      VARCHAR(64)  NOT NULL,
+    token_hash     VARCHAR(64)  NOT NULL UNIQUE,
+    expires_at     TIMESTAMP    NOT NULL,
+    revoked_at     TIMESTAMP    NULL DEFAULT NULL,
+    replaced_by_id INT          NULL DEFAULT NULL,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    INDEX          idx_refresh_tokens_user_id (user_id),
+    INDEX          idx_refresh_tokens_family_id (family_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'author' AFTER password_hash,
+    ADD INDEX idx_users_role (role);
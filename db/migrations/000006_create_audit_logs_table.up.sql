@@ -0,0 +1,14 @@
+CREATE TABLE audit_logs
+(
+    id            INT AUTO_INCREMENT PRIMARY KEY,
+    actor_id      INT          NOT NULL,
+    actor_role    VARCHAR(20)  NOT NULL,
+    action        VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50)  NOT NULL,
+    resource_id   INT          NOT NULL,
+    details       TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX         idx_audit_logs_actor_id (actor_id),
+    INDEX         idx_audit_logs_resource (resource_type, resource_id),
+    INDEX         idx_audit_logs_created_at (created_at)
+);
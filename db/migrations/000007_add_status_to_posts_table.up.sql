@@ -0,0 +1,6 @@
+ALTER TABLE posts
+    ADD COLUMN status       VARCHAR(20) NOT NULL DEFAULT 'published' AFTER author_id,
+    ADD COLUMN published_at TIMESTAMP   NULL DEFAULT NULL AFTER status,
+    ADD INDEX idx_posts_status_published_at (status, published_at);
+
+UPDATE posts SET published_at = created_at WHERE published_at IS NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE post_revisions
+(
+    id         INT AUTO_INCREMENT PRIMARY KEY,
+    post_id    INT          NOT NULL,
+    revision   INT          NOT NULL,
+    title      VARCHAR(255) NOT NULL,
+    content    TEXT         NOT NULL,
+    editor_id  INT          NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_post_revisions_post_id_revision (post_id, revision),
+    INDEX      idx_post_revisions_editor_id (editor_id)
+);
+
+INSERT INTO post_revisions (post_id, revision, title, content, editor_id, created_at)
+SELECT id, 1, title, content, author_id, updated_at
+FROM posts;
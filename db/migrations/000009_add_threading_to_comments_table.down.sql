@@ -0,0 +1,7 @@
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_comments_parent_id,
+    DROP INDEX idx_comments_post_id_parent_id,
+    DROP INDEX idx_comments_root_id,
+    DROP COLUMN depth,
+    DROP COLUMN root_id,
+    DROP COLUMN parent_id;
@@ -0,0 +1,7 @@
+ALTER TABLE comments
+    ADD COLUMN parent_id INT NULL DEFAULT NULL AFTER post_id,
+    ADD COLUMN root_id   INT NULL DEFAULT NULL AFTER parent_id,
+    ADD COLUMN depth     INT NOT NULL DEFAULT 0 AFTER root_id,
+    ADD CONSTRAINT fk_comments_parent_id FOREIGN KEY (parent_id) REFERENCES comments (id) ON DELETE CASCADE,
+    ADD INDEX idx_comments_post_id_parent_id (post_id, parent_id),
+    ADD INDEX idx_comments_root_id (root_id);
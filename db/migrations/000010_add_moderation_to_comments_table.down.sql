@@ -0,0 +1,11 @@
+ALTER TABLE posts
+    DROP COLUMN comment_policy;
+
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_comments_user_id,
+    DROP INDEX idx_comments_post_id_status,
+    DROP INDEX idx_comments_status_created_at,
+    DROP COLUMN moderated_at,
+    DROP COLUMN moderated_by,
+    DROP COLUMN status,
+    DROP COLUMN user_id;
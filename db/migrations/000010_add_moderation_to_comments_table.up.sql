@@ -0,0 +1,11 @@
+ALTER TABLE comments
+    ADD COLUMN status       VARCHAR(20) NOT NULL DEFAULT 'approved' AFTER content,
+    ADD COLUMN user_id      INT         NULL DEFAULT NULL AFTER depth,
+    ADD COLUMN moderated_by INT         NULL DEFAULT NULL AFTER status,
+    ADD COLUMN moderated_at TIMESTAMP   NULL DEFAULT NULL AFTER moderated_by,
+    ADD CONSTRAINT fk_comments_user_id FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL,
+    ADD INDEX idx_comments_post_id_status (post_id, status),
+    ADD INDEX idx_comments_status_created_at (status, created_at);
+
+ALTER TABLE posts
+    ADD COLUMN comment_policy VARCHAR(20) NOT NULL DEFAULT 'inherit' AFTER published_at;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS spam_tokens;
+
+ALTER TABLE comments
+    DROP COLUMN spam_reasons,
+    DROP COLUMN spam_score;
@@ -0,0 +1,11 @@
+ALTER TABLE comments
+    ADD COLUMN spam_score   DECIMAL(5, 4) NOT NULL DEFAULT 0 AFTER status,
+    ADD COLUMN spam_reasons TEXT          NULL AFTER spam_score;
+
+CREATE TABLE spam_tokens
+(
+    token      VARCHAR(64) PRIMARY KEY,
+    spam_count INT NOT NULL DEFAULT 0,
+    ham_count  INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
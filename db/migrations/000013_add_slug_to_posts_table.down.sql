@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS post_slug_aliases;
+
+ALTER TABLE posts
+    DROP INDEX idx_posts_slug,
+    DROP COLUMN slug;
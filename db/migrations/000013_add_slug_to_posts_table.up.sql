@@ -0,0 +1,22 @@
+ALTER TABLE posts
+    ADD COLUMN slug VARCHAR(191) NULL AFTER title;
+
+-- Titles were HTML-escaped before they were stored: build the slugs from the unescaped text
+UPDATE posts
+SET slug = TRIM(BOTH '-' FROM CONCAT(LOWER(REGEXP_REPLACE(LEFT(
+        REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(title, '&lt;', '<'), '&gt;', '>'), '&#34;', '"'), '&#39;', ''''), '&amp;', '&'),
+        80), '[^[:alnum:]]+', '-')), '-', id))
+WHERE slug IS NULL;
+
+ALTER TABLE posts
+    MODIFY COLUMN slug VARCHAR(191) NOT NULL,
+    ADD UNIQUE INDEX idx_posts_slug (slug);
+
+CREATE TABLE post_slug_aliases
+(
+    slug       VARCHAR(191) PRIMARY KEY,
+    post_id    INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    INDEX idx_post_slug_aliases_post_id (post_id)
+);
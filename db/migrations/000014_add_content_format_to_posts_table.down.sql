@@ -0,0 +1,8 @@
+UPDATE post_revisions
+SET content = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;');
+
+UPDATE posts
+SET content = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;');
+
+ALTER TABLE posts
+    DROP COLUMN content_format;
@@ -0,0 +1,10 @@
+ALTER TABLE posts
+    ADD COLUMN content_format VARCHAR(20) NOT NULL DEFAULT 'markdown' AFTER content;
+
+-- Existing content was HTML-escaped before it was stored: unescape it and keep rendering it as plain text
+UPDATE posts
+SET content        = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(content, '&lt;', '<'), '&gt;', '>'), '&#34;', '"'), '&#39;', ''''), '&amp;', '&'),
+    content_format = 'plain';
+
+UPDATE post_revisions
+SET content = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(content, '&lt;', '<'), '&gt;', '>'), '&#34;', '"'), '&#39;', ''''), '&amp;', '&');
@@ -0,0 +1,4 @@
+ALTER TABLE posts
+    DROP INDEX ft_posts_content,
+    DROP INDEX ft_posts_title,
+    DROP INDEX ft_posts_title_content;
@@ -0,0 +1,3 @@
+ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_title_content (title, content);
+ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_title (title);
+ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_content (content);
@@ -0,0 +1,20 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP NULL DEFAULT NULL AFTER role;
+
+-- Accounts created before verification existed are trusted as they are
+UPDATE users
+SET email_verified_at = created_at
+WHERE email_verified_at IS NULL;
+
+CREATE TABLE user_tokens
+(
+    id         INT AUTO_INCREMENT PRIMARY KEY,
+    user_id    INT         NOT NULL,
+    purpose    VARCHAR(32) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP   NOT NULL,
+    used_at    TIMESTAMP   NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    INDEX      idx_user_tokens_user_id_purpose (user_id, purpose)
+);
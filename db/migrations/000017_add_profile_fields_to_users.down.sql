@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN pending_email,
+    DROP COLUMN website,
+    DROP COLUMN avatar_url,
+    DROP COLUMN bio;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN bio           VARCHAR(1000) NOT NULL DEFAULT '' AFTER role,
+    ADD COLUMN avatar_url    VARCHAR(2048) NOT NULL DEFAULT '' AFTER bio,
+    ADD COLUMN website       VARCHAR(2048) NOT NULL DEFAULT '' AFTER avatar_url,
+    ADD COLUMN pending_email VARCHAR(255)  NULL DEFAULT NULL AFTER email;
@@ -0,0 +1,5 @@
+UPDATE post_revisions
+SET title = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;');
+
+UPDATE posts
+SET title = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;');
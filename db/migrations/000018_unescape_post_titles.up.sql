@@ -0,0 +1,6 @@
+-- Titles were HTML-escaped before they were stored; they are now stored as written and escaped in responses
+UPDATE posts
+SET title = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(title, '&lt;', '<'), '&gt;', '>'), '&#34;', '"'), '&#39;', ''''), '&amp;', '&');
+
+UPDATE post_revisions
+SET title = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(title, '&lt;', '<'), '&gt;', '>'), '&#34;', '"'), '&#39;', ''''), '&amp;', '&');
@@ -0,0 +1,7 @@
+ALTER TABLE post_revisions
+    ADD COLUMN content_format VARCHAR(20) NOT NULL DEFAULT 'markdown' AFTER content;
+
+-- The format of older revisions was not recorded: assume the one their post is written in now
+UPDATE post_revisions
+    JOIN posts ON posts.id = post_revisions.post_id
+SET post_revisions.content_format = posts.content_format;
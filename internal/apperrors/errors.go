@@ -0,0 +1,91 @@
+// Package apperrors defines the errors services return. The kind of an error decides the HTTP status
+// it is answered with, and its code is a stable, machine-readable name clients can rely on.
+package apperrors
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+// Kind classifies an error
+type Kind string
+
+// Error kinds
+const (
+	KindNotFound     Kind = "not_found"
+	KindForbidden    Kind = "forbidden"
+	KindConflict     Kind = "conflict"
+	KindValidation   Kind = "validation"
+	KindUnauthorized Kind = "unauthorized"
+	KindUnavailable  Kind = "unavailable"
+)
+
+// Sentinels matching every error of their kind with errors.Is
+var (
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrConflict     = &Error{Kind: KindConflict}
+	ErrValidation   = &Error{Kind: KindValidation}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrUnavailable  = &Error{Kind: KindUnavailable}
+)
+
+// Error is a domain error. Message is shown to clients; the wrapped cause is only logged.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Fields lists the invalid fields of a validation error
+	Fields []dto.FieldError
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches a sentinel of the same kind, or an error of the same kind and code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || t.Kind != e.Kind {
+		return false
+	}
+	return t.Code == "" || t.Code == e.Code
+}
+
+// NotFound is returned when the requested resource does not exist, or is not visible to the caller
+func NotFound(code string, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Forbidden is returned when the caller may not perform the action
+func Forbidden(code string, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// Conflict is returned when the action clashes with the current state, e.g. a value already in use
+func Conflict(code string, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Validation is returned for invalid input, optionally listing the invalid fields
+func Validation(code string, message string, fields ...dto.FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+// Unauthorized is returned when the caller's credentials are wrong
+func Unauthorized(code string, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Unavailable is returned when a dependency such as the database or Redis failed, wrapping the cause
+func Unavailable(message string, err error) *Error {
+	return &Error{Kind: KindUnavailable, Code: "service_unavailable", Message: message, Err: err}
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// AccountController struct
+type AccountController struct {
+	accountService services.AccountService
+}
+
+// NewAccountController initializes account controller
+func NewAccountController(accountService services.AccountService) *AccountController {
+	return &AccountController{accountService: accountService}
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Mail a single-use password reset link to the address. The response is the same whether or not the email is registered.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param forgotPassword body dto.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Router /password/forgot [post]
+func (c *AccountController) ForgotPassword(ctx *gin.Context) {
+	var forgotDto dto.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&forgotDto); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	if err := c.accountService.ForgotPassword(ctx.Request.Context(), forgotDto.Email); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
+
+// ResetPassword godoc
+// @Summary Reset a password
+// @Description Choose a new password with the token of a reset link. Every session of the account is signed out.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param resetPassword body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Router /password/reset [post]
+func (c *AccountController) ResetPassword(ctx *gin.Context) {
+	var resetDto dto.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&resetDto); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	if err := c.accountService.ResetPassword(ctx.Request.Context(), resetDto.Token, resetDto.Password); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
+
+// VerifyEmail godoc
+// @Summary Verify an email address
+// @Description Redeem the token of an email verification link
+// @Tags Account
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Router /verify-email [get]
+func (c *AccountController) VerifyEmail(ctx *gin.Context) {
+	if err := c.accountService.VerifyEmail(ctx.Request.Context(), ctx.Query("token")); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
+
+// ResendVerification godoc
+// @Summary Resend the email verification link
+// @Description Mail a new verification link to the signed-in user; earlier links stop working
+// @Tags Account
+// @Produce json
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Router /verify-email/resend [post]
+// @Security BearerAuth
+func (c *AccountController) ResendVerification(ctx *gin.Context) {
+	if err := c.accountService.ResendEmailVerification(ctx.Request.Context(), currentViewerID(ctx)); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm a new email address
+// @Description Redeem the token of an email change link, switching the account to the new address
+// @Tags Account
+// @Produce json
+// @Param token query string true "Email change token"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Router /email/confirm [get]
+func (c *AccountController) ConfirmEmailChange(ctx *gin.Context) {
+	if err := c.accountService.ConfirmEmailChange(ctx.Request.Context(), ctx.Query("token")); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
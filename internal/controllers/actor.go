@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// currentActor builds the acting user from the values set by the auth middleware
+func currentActor(ctx *gin.Context) *dto.Actor {
+	return &dto.Actor{
+		ID:   ctx.MustGet("userID").(uint),
+		Role: ctx.GetString("userRole"),
+	}
+}
+
+// currentViewer builds the viewing user from the values set by the optional auth middleware, with
+// a zero ID and no role for anonymous requests
+func currentViewer(ctx *gin.Context) *dto.Actor {
+	return &dto.Actor{
+		ID:   ctx.GetUint("userID"),
+		Role: ctx.GetString("userRole"),
+	}
+}
+
+// currentViewerID returns the authenticated user ID, or 0 for anonymous requests
+func currentViewerID(ctx *gin.Context) uint {
+	return ctx.GetUint("userID")
+}
+
+// wantsTotalCount reports whether a list response should include total_count. Counting can be skipped
+// with with_count=false, which saves a query when following cursors through large lists.
+func wantsTotalCount(ctx *gin.Context) bool {
+	withCount, err := strconv.ParseBool(ctx.DefaultQuery("with_count", "true"))
+	return err != nil || withCount
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// AuditController struct
+type AuditController struct {
+	auditService services.AuditService
+}
+
+// NewAuditController initializes audit controller
+func NewAuditController(auditService services.AuditService) *AuditController {
+	return &AuditController{auditService: auditService}
+}
+
+// GetAll godoc
+// @Summary Get audit logs
+// @Description Retrieve the privileged actions recorded by the system, newest first (admins only)
+// @Tags Audit
+// @Produce json
+// @Param search query string false "Search by action or resource type"
+// @Param action query string false "Filter by action, also action[in]=a,b"
+// @Param resource_type query string false "Filter by resource type"
+// @Param actor_id query int false "Filter by actor"
+// @Param created_after query string false "Recorded on or after (2006-01-02 or RFC 3339), also created_before"
+// @Param sort query string false "created_at or id, prefix - for descending; default -created_at"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.AuditLogResponse}}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /audit-logs [get]
+// @Security BearerAuth
+func (c *AuditController) GetAll(ctx *gin.Context) {
+	queryParams, err := helpers.ParseListQuery(ctx.Request.URL.Query(), dto.AuditLogListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	auditLogResponses, err := c.auditService.GetAll(ctx.Request.Context(), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	totalCount, err := c.auditService.Count(ctx.Request.Context(), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponsePagination(auditLogResponses, totalCount))
+}
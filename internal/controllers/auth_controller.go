@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// AuthController struct
+type AuthController struct {
+	authService services.AuthService
+}
+
+// NewAuthController initializes auth controller
+func NewAuthController(authService services.AuthService) *AuthController {
+	return &AuthController{authService: authService}
+}
+
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and a rotated refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param refresh body dto.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} dto.BaseResponse{data=dto.TokenResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Router /token/refresh [post]
+func (c *AuthController) Refresh(ctx *gin.Context) {
+	var refreshDto dto.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&refreshDto); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	tokenResponse, err := c.authService.Refresh(ctx.Request.Context(), refreshDto.RefreshToken)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(tokenResponse))
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the current access token and the given refresh token session (or all sessions)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param logout body dto.LogoutRequest false "Logout options"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /logout [post]
+// @Security BearerAuth
+func (c *AuthController) Logout(ctx *gin.Context) {
+	var logoutDto dto.LogoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&logoutDto); err != nil {
+			_ = ctx.Error(invalidPayload(err))
+			return
+		}
+	}
+
+	claims := ctx.MustGet("claims").(*helpers.Claims)
+	if err := c.authService.Logout(ctx.Request.Context(), claims, &logoutDto); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
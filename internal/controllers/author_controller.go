@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// AuthorController struct
+type AuthorController struct {
+	authorService services.AuthorService
+	postService   services.PostService
+}
+
+// NewAuthorController initializes author controller
+func NewAuthorController(authorService services.AuthorService, postService services.PostService) *AuthorController {
+	return &AuthorController{authorService: authorService, postService: postService}
+}
+
+// GetByID godoc
+// @Summary Get an author's public profile
+// @Description Get the public profile of an author with the number of posts they published and when they joined. The email is only included for the author themselves and for admins.
+// @Tags Authors
+// @Produce json
+// @Param id path int true "Author ID"
+// @Success 200 {object} dto.BaseResponse{data=dto.AuthorProfileResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /authors/{id} [get]
+func (c *AuthorController) GetByID(ctx *gin.Context) {
+	authorID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || authorID <= 0 {
+		_ = ctx.Error(invalidID("author"))
+		return
+	}
+
+	authorResponse, err := c.authorService.GetProfile(ctx.Request.Context(), uint(authorID), currentViewer(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(authorResponse))
+}
+
+// GetPosts godoc
+// @Summary Get an author's posts
+// @Description List the posts of an author with the same filters, sorting and pagination as GET /posts. Only published posts are listed, except for the author themselves.
+// @Tags Authors
+// @Produce json
+// @Param id path int true "Author ID"
+// @Param search query string false "Search by title or content"
+// @Param status query string false "Filter by status; non-published posts are limited to your own"
+// @Param tag query string false "Filter by tag slug, also tag[in]=a,b"
+// @Param category query string false "Filter by category slug, also category[in]=a,b"
+// @Param sort query string false "Comma separated fields, prefix - for descending (created_at, updated_at, id, published_at, title); default -created_at"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param cursor query string false "next_cursor or prev_cursor of a previous page (created_at, updated_at and id sorts)"
+// @Param with_count query bool false "Include total_count (default true)"
+// @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.PostResponse}}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /authors/{id}/posts [get]
+func (c *AuthorController) GetPosts(ctx *gin.Context) {
+	authorID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || authorID <= 0 {
+		_ = ctx.Error(invalidID("author"))
+		return
+	}
+
+	queryParams, err := helpers.ParseListQuery(ctx.Request.URL.Query(), dto.PostListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	queryParams.ViewerID = currentViewerID(ctx)
+
+	postResponses, cursors, err := c.authorService.GetPosts(ctx.Request.Context(), uint(authorID), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	var totalCount *int64
+	if wantsTotalCount(ctx) {
+		// The list query is scoped to the author by GetPosts
+		count, err := c.postService.Count(ctx.Request.Context(), queryParams)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		totalCount = &count
+	}
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponseCursorPagination(postResponses, totalCount, cursors))
+}
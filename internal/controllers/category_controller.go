@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// CategoryController struct
+type CategoryController struct {
+	categoryService services.CategoryService
+}
+
+// NewCategoryController initializes category controller
+func NewCategoryController(categoryService services.CategoryService) *CategoryController {
+	return &CategoryController{categoryService: categoryService}
+}
+
+// Create godoc
+// @Summary Create a category
+// @Description Add a category that posts can be filed under (editors and admins only)
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param category body dto.CategoryRequest true "Category details"
+// @Success 200 {object} dto.BaseResponse{data=dto.CategoryResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Router /categories [post]
+// @Security BearerAuth
+func (c *CategoryController) Create(ctx *gin.Context) {
+	var categoryRequest dto.CategoryRequest
+	if err := ctx.ShouldBindJSON(&categoryRequest); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	categoryResponse, err := c.categoryService.Create(ctx.Request.Context(), &categoryRequest, currentActor(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(categoryResponse))
+}
+
+// GetAll godoc
+// @Summary Get categories
+// @Description Retrieve every category with its number of published posts, most used first
+// @Tags Categories
+// @Produce json
+// @Param search query string false "Search by category name"
+// @Param sort query string false "post_count or name, prefix - for descending; default -post_count,name"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.CategoryResponse}}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /categories [get]
+func (c *CategoryController) GetAll(ctx *gin.Context) {
+	queryParams, err := helpers.ParseListQuery(ctx.Request.URL.Query(), dto.CategoryListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	categoryResponses, err := c.categoryService.GetAll(ctx.Request.Context(), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	totalCount, err := c.categoryService.Count(ctx.Request.Context(), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponsePagination(categoryResponses, totalCount))
+}
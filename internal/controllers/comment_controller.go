@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 	"github.com/dedenfarhanhub/blog-service/internal/helpers"
 	"github.com/dedenfarhanhub/blog-service/internal/services"
@@ -23,7 +24,7 @@ func NewCommentController(commentService services.CommentService) *CommentContro
 
 // Create godoc
 // @Summary Create a new comment
-// @Description Add a new comment to a post
+// @Description Add a new comment to a post. Depending on the moderation policy the comment may wait for approval.
 // @Tags Comments
 // @Accept json
 // @Produce json
@@ -31,28 +32,31 @@ func NewCommentController(commentService services.CommentService) *CommentContro
 // @Param comment body dto.CommentRequest true "Comment details"
 // @Success 200 {object} dto.BaseResponse{data=dto.CommentResponse}
 // @Failure 400 {object} dto.BaseResponse
-// @Failure 500 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
 // @Router /posts/{id}/comments [post]
 func (c *CommentController) Create(ctx *gin.Context) {
 	postIDParam := ctx.Param("id")
 	postID, err := strconv.Atoi(postIDParam)
 	if err != nil || postID <= 0 {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(http.StatusBadRequest, "Invalid Post ID"))
+		_ = ctx.Error(invalidID("post"))
 		return
 	}
 
 	var commentDto dto.CommentRequest
 	if err := ctx.ShouldBindJSON(&commentDto); err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		_ = ctx.Error(invalidPayload(err))
 		return
 	}
 
 	// Sanitasi input untuk mencegah XSS
 	commentDto.Content = html.EscapeString(commentDto.Content)
+	commentDto.UserID = currentViewerID(ctx)
+	commentDto.ClientIP = ctx.ClientIP()
 
-	commentResponses, err := c.commentService.Create(uint(postID), &commentDto)
+	commentResponses, err := c.commentService.Create(ctx.Request.Context(), uint(postID), &commentDto)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, helpers.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		_ = ctx.Error(err)
 		return
 	}
 
@@ -61,47 +65,179 @@ func (c *CommentController) Create(ctx *gin.Context) {
 
 // GetAllByPostID godoc
 // @Summary Get all comments by post ID
-// @Description Retrieve all comments associated with a specific post
+// @Description Retrieve the approved comments of a post, plus the caller's own pending ones. With view=tree, top-level comments are paginated and returned with their nested replies.
 // @Tags Comments
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param view query string false "Response shape (flat, tree)"
 // @Param page query int false "Page number"
 // @Param page_size query int false "Number of comments per page"
 // @Param search query string false "Search by comment content"
-// @Param sort_by query string false "Sort by field"
-// @Param sort_order query string false "Sort order (asc, desc)"
+// @Param user_id query int false "Filter by commenter, also user_id[in]=1,2"
+// @Param created_after query string false "Created on or after (2006-01-02 or RFC 3339), also created_before"
+// @Param sort query string false "Comma separated fields, prefix - for descending (created_at, id); default created_at"
+// @Param sort_by query string false "Sort by field (deprecated, use sort)"
+// @Param sort_order query string false "Sort order for sort_by (asc, desc)"
+// @Param cursor query string false "next_cursor or prev_cursor of a previous page (flat view, created_at and id sorts)"
+// @Param with_count query bool false "Include total_count (default true)"
 // @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.CommentResponse}}
+// @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.CommentTreeResponse}}
+// @Failure 400 {object} dto.BaseResponse
 // @Failure 500 {object} dto.BaseResponse
 // @Router /posts/{id}/comments [get]
 func (c *CommentController) GetAllByPostID(ctx *gin.Context) {
-	id := ctx.Param("id")
-	page := ctx.Query("page")
-	pageSize := ctx.Query("page_size")
+	postID, _ := strconv.Atoi(ctx.Param("id"))
+
+	queryParams, err := helpers.ParseListQuery(ctx.Request.URL.Query(), dto.CommentListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	queryParams.ViewerID = currentViewerID(ctx)
+
+	switch ctx.DefaultQuery("view", "flat") {
+	case "tree":
+		c.getTreeByPostID(ctx, uint(postID), queryParams)
+		return
+	case "flat":
+	default:
+		_ = ctx.Error(apperrors.Validation("invalid_view", "Invalid view, expected flat or tree",
+			dto.FieldError{Field: "view", Message: "must be one of: flat, tree"}))
+		return
+	}
+
+	commentResponses, cursors, err := c.commentService.GetAllByPostID(ctx.Request.Context(), uint(postID), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	var totalCount *int64
+	if wantsTotalCount(ctx) {
+		count, err := c.commentService.CountAllByPostID(ctx.Request.Context(), uint(postID), queryParams)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		totalCount = &count
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponseCursorPagination(commentResponses, totalCount, cursors))
+}
+
+// getTreeByPostID responds with a page of top-level comments and their nested replies
+func (c *CommentController) getTreeByPostID(ctx *gin.Context, postID uint, queryParams *dto.ListQuery) {
+	treeResponses, err := c.commentService.GetTreeByPostID(ctx.Request.Context(), postID, queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	totalCount, err := c.commentService.CountRootsByPostID(ctx.Request.Context(), postID, queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponsePagination(treeResponses, totalCount))
+}
+
+// Delete godoc
+// @Summary Delete a comment
+// @Description Remove a comment from a post (moderators only)
+// @Tags Comments
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param commentId path int true "Comment ID"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Router /posts/{id}/comments/{commentId} [delete]
+// @Security BearerAuth
+func (c *CommentController) Delete(ctx *gin.Context) {
+	postID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || postID <= 0 {
+		_ = ctx.Error(invalidID("post"))
+		return
+	}
 
-	postID, _ := strconv.Atoi(id)
-	pageInt, _ := strconv.Atoi(page)
-	pageSizeInt, _ := strconv.Atoi(pageSize)
+	commentID, err := strconv.Atoi(ctx.Param("commentId"))
+	if err != nil || commentID <= 0 {
+		_ = ctx.Error(invalidID("comment"))
+		return
+	}
 
-	queryParams := dto.QueryParams{
-		Search:    ctx.Query("search"),
-		SortBy:    ctx.Query("sort_by"),
-		SortOrder: ctx.Query("sort_order"),
+	if err := c.commentService.Delete(ctx.Request.Context(), uint(postID), uint(commentID), currentActor(ctx)); err != nil {
+		_ = ctx.Error(err)
+		return
 	}
 
-	queryParams.Page = pageInt
-	queryParams.PageSize = pageSizeInt
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
+
+// GetModerationQueue godoc
+// @Summary Get the comment moderation queue
+// @Description List comments in a moderation status (pending by default), oldest first
+// @Tags Moderation
+// @Produce json
+// @Param status query string false "Moderation status (pending, approved, rejected, spam)"
+// @Param post_id query int false "Limit to a post, also post_id[in]=1,2"
+// @Param spam_score[gte] query number false "Minimum spam score"
+// @Param sort query string false "Comma separated fields, prefix - for descending (created_at, spam_score, id); default created_at"
+// @Param search query string false "Search by author name or content"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.CommentResponse}}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Router /moderation/comments [get]
+// @Security BearerAuth
+func (c *CommentController) GetModerationQueue(ctx *gin.Context) {
+	queryParams, err := helpers.ParseListQuery(ctx.Request.URL.Query(), dto.ModerationListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
 
-	commentResponses, err := c.commentService.GetAllByPostID(uint(postID), &queryParams)
+	commentResponses, err := c.commentService.GetModerationQueue(ctx.Request.Context(), queryParams)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, helpers.NewErrorResponse(http.StatusInternalServerError, "Failed to retrieve comments"))
+		_ = ctx.Error(err)
 		return
 	}
 
-	totalCount, err := c.commentService.CountAllByPostID(uint(postID), &queryParams)
+	totalCount, err := c.commentService.CountModerationQueue(ctx.Request.Context(), queryParams)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, helpers.NewErrorResponse(http.StatusInternalServerError, "Failed to retrieve posts"))
+		_ = ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, helpers.NewSuccessResponsePagination(commentResponses, totalCount))
 }
+
+// Moderate godoc
+// @Summary Moderate comments in bulk
+// @Description Approve, reject or mark as spam up to 100 comments at once
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param moderation body dto.ModerateCommentsRequest true "Comments and their new status"
+// @Success 200 {object} dto.BaseResponse{data=dto.ModerateCommentsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Router /moderation/comments/status [put]
+// @Security BearerAuth
+func (c *CommentController) Moderate(ctx *gin.Context) {
+	var moderateDto dto.ModerateCommentsRequest
+	if err := ctx.ShouldBindJSON(&moderateDto); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	moderateResponse, err := c.commentService.Moderate(ctx.Request.Context(), &moderateDto, currentActor(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(moderateResponse))
+}
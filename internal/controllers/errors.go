@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report invalid fields by their JSON names
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// invalidID is the error for a path ID that is not a number
+func invalidID(resource string) error {
+	return apperrors.Validation("invalid_id", "Invalid "+resource+" ID")
+}
+
+// invalidRevision is the error for a revision number that is not a positive integer
+func invalidRevision(param string) error {
+	return apperrors.Validation("invalid_revision", "Invalid revision",
+		dto.FieldError{Field: param, Message: "must be a positive integer"})
+}
+
+// invalidPayload turns a binding error into a validation error listing the invalid fields
+func invalidPayload(err error) error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]dto.FieldError, 0, len(validationErrors))
+		for _, fieldErr := range validationErrors {
+			fields = append(fields, dto.FieldError{Field: fieldErr.Field(), Message: fieldMessage(fieldErr)})
+		}
+		return apperrors.Validation("invalid_payload", "Invalid request payload", fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return apperrors.Validation("invalid_payload", "Invalid request payload",
+			dto.FieldError{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()})
+	}
+	return apperrors.Validation("invalid_payload", "Invalid request payload")
+}
+
+// fieldMessage describes the rule a field broke
+func fieldMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return "must have at least " + fieldErr.Param() + " " + lengthUnit(fieldErr)
+	case "max":
+		return "must have at most " + fieldErr.Param() + " " + lengthUnit(fieldErr)
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	default:
+		return "is invalid (" + fieldErr.Tag() + ")"
+	}
+}
+
+// lengthUnit is what the min and max rules count for the field
+func lengthUnit(fieldErr validator.FieldError) string {
+	switch fieldErr.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	default:
+		return "characters"
+	}
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// HealthController struct
+type HealthController struct {
+	healthService services.HealthService
+}
+
+// NewHealthController initializes health controller
+func NewHealthController(healthService services.HealthService) *HealthController {
+	return &HealthController{healthService: healthService}
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Answers as long as the server runs, without checking its dependencies.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Router /healthz [get]
+func (c *HealthController) Liveness(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, dto.HealthResponse{Status: "ok"})
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Pings MySQL and Redis and checks the schema migration version, each with a timeout. Answers 503 when a check fails or the server is shutting down.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.ReadinessResponse
+// @Failure 503 {object} dto.ReadinessResponse
+// @Router /readyz [get]
+func (c *HealthController) Readiness(ctx *gin.Context) {
+	readiness := c.healthService.Readiness(ctx.Request.Context())
+
+	status := http.StatusOK
+	if readiness.Status != services.ReadinessReady {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(status, readiness)
+}
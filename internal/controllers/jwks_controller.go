@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// JWKSController struct
+type JWKSController struct {
+	jwtKeys *helpers.JWTKeys
+}
+
+// NewJWKSController initializes JWKS controller
+func NewJWKSController(jwtKeys *helpers.JWTKeys) *JWKSController {
+	return &JWKSController{jwtKeys: jwtKeys}
+}
+
+// GetJWKS godoc
+// @Summary Get the access token verification keys
+// @Description The public keys access tokens are signed with, as a JSON Web Key Set, so other services can verify tokens. Empty when tokens are signed with the shared HS256 secret.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dto.JWKSResponse
+// @Router /.well-known/jwks.json [get]
+func (c *JWKSController) GetJWKS(ctx *gin.Context) {
+	// Verifiers may cache the keys for a while; new keys are published ahead of a rotation
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.jwtKeys.JWKS())
+}
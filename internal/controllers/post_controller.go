@@ -5,8 +5,8 @@ import (
 	"github.com/dedenfarhanhub/blog-service/internal/helpers"
 	"github.com/dedenfarhanhub/blog-service/internal/services"
 	"github.com/gin-gonic/gin"
-	"html"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
@@ -34,18 +34,15 @@ func NewPostController(postService services.PostService) *PostController {
 func (c *PostController) Create(ctx *gin.Context) {
 	var postRequest dto.PostRequest
 	if err := ctx.ShouldBindJSON(&postRequest); err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(400, "Invalid request payload"))
+		_ = ctx.Error(invalidPayload(err))
 		return
 	}
 
 	userID := ctx.MustGet("userID").(uint)
 	postRequest.AuthorID = userID
-	// Sanitasi input untuk mencegah XSS
-	postRequest.Title = html.EscapeString(postRequest.Title)
-	postRequest.Content = html.EscapeString(postRequest.Content)
-	postResponse, err := c.postService.CreatePost(&postRequest)
+	postResponse, err := c.postService.CreatePost(ctx.Request.Context(), &postRequest)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(400, err.Error()))
+		_ = ctx.Error(err)
 		return
 	}
 
@@ -62,30 +59,30 @@ func (c *PostController) Create(ctx *gin.Context) {
 // @Param postRequest body dto.PostRequest true "Post Request"
 // @Success 200 {object} dto.BaseResponse{data=dto.PostResponse}
 // @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
 // @Router /posts/{id} [put]
 // @Security BearerAuth
 func (c *PostController) Update(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(400, "Invalid post ID"))
+		_ = ctx.Error(invalidID("post"))
 		return
 	}
 
 	var postRequest dto.PostRequest
 	if err := ctx.ShouldBindJSON(&postRequest); err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(400, "Invalid request payload"))
+		_ = ctx.Error(invalidPayload(err))
 		return
 	}
 
-	userID := ctx.MustGet("userID").(uint)
-	postRequest.AuthorID = userID
-	// Sanitasi input untuk mencegah XSS
-	postRequest.Title = html.EscapeString(postRequest.Title)
-	postRequest.Content = html.EscapeString(postRequest.Content)
-	postResponse, err := c.postService.Update(uint(id), &postRequest)
+	actor := currentActor(ctx)
+	postResponse, err := c.postService.Update(ctx.Request.Context(), uint(id), &postRequest, actor)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(400, err.Error()))
+		_ = ctx.Error(err)
 		return
 	}
 
@@ -94,24 +91,52 @@ func (c *PostController) Update(ctx *gin.Context) {
 
 // GetByID godoc
 // @Summary Get a post by ID
-// @Description Get details of a post by its ID
+// @Description Get details of a post by its ID. Drafts, scheduled and archived posts are only visible to their author.
 // @Tags Posts
 // @Produce  json
 // @Param id path int true "Post ID"
 // @Success 200 {object} dto.BaseResponse{data=dto.PostResponse}
 // @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
 // @Router /posts/{id} [get]
 func (c *PostController) GetByID(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(400, "Invalid post ID"))
+		_ = ctx.Error(invalidID("post"))
 		return
 	}
 
-	postResponse, err := c.postService.GetPostByID(uint(id))
+	postResponse, err := c.postService.GetPostByID(ctx.Request.Context(), uint(id), currentViewerID(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, helpers.NewErrorResponse(404, err.Error()))
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(postResponse))
+}
+
+// GetBySlug godoc
+// @Summary Get a post by slug
+// @Description Get details of a post by its slug. Previous slugs of a renamed post redirect to the current one.
+// @Tags Posts
+// @Produce  json
+// @Param slug path string true "Post slug"
+// @Success 200 {object} dto.BaseResponse{data=dto.PostResponse}
+// @Success 301 "Redirect to the current slug"
+// @Failure 404 {object} dto.BaseResponse
+// @Router /posts/by-slug/{slug} [get]
+func (c *PostController) GetBySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	postResponse, err := c.postService.GetPostBySlug(ctx.Request.Context(), slug, currentViewerID(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if postResponse.Slug != slug {
+		ctx.Redirect(http.StatusMovedPermanently, "/posts/by-slug/"+url.PathEscape(postResponse.Slug))
 		return
 	}
 
@@ -126,20 +151,22 @@ func (c *PostController) GetByID(ctx *gin.Context) {
 // @Param id path int true "Post ID"
 // @Success 200 {object} dto.BaseResponse
 // @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
 // @Router /posts/{id} [delete]
 // @Security BearerAuth
 func (c *PostController) Delete(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(400, "Invalid post ID"))
+		_ = ctx.Error(invalidID("post"))
 		return
 	}
 
-	userID := ctx.MustGet("userID").(uint)
-	err = c.postService.Delete(uint(id), userID)
+	err = c.postService.Delete(ctx.Request.Context(), uint(id), currentActor(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, helpers.NewErrorResponse(404, err.Error()))
+		_ = ctx.Error(err)
 		return
 	}
 
@@ -148,40 +175,112 @@ func (c *PostController) Delete(ctx *gin.Context) {
 
 // GetAll godoc
 // @Summary Get all posts
-// @Description Retrieve all posts with filters
+// @Description Retrieve all posts with filters. Unknown parameters, fields or operators are rejected with 400.
 // @Tags Posts
 // @Produce  json
 // @Param search query string false "Search by title or content"
-// @Param sort_by query string false "Sort by field"
-// @Param sort_order query string false "Sort order (asc, desc)"
+// @Param status query string false "Filter by status (draft, scheduled, published, archived), also status[in]=draft,scheduled; non-published posts are limited to your own"
+// @Param author_id query int false "Filter by author, also author_id[in]=1,2"
+// @Param tag query string false "Filter by tag slug, also tag[in]=a,b"
+// @Param category query string false "Filter by category slug, also category[in]=a,b"
+// @Param created_after query string false "Created on or after (2006-01-02 or RFC 3339); also created_before, published_after/before, updated_after/before and field[gte|gt|lte|lt]=..."
+// @Param sort query string false "Comma separated fields, prefix - for descending (created_at, updated_at, id, published_at, title); default -created_at"
+// @Param sort_by query string false "Sort by field (deprecated, use sort)"
+// @Param sort_order query string false "Sort order for sort_by (asc, desc)"
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
+// @Param cursor query string false "next_cursor or prev_cursor of a previous page (created_at, updated_at and id sorts)"
+// @Param with_count query bool false "Include total_count (default true)"
 // @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.PostResponse}}
+// @Failure 400 {object} dto.BaseResponse
 // @Failure 500 {object} dto.BaseResponse
 // @Router /posts [get]
 func (c *PostController) GetAll(ctx *gin.Context) {
-	queryParams := dto.QueryParams{
-		Search:    ctx.Query("search"),
-		SortBy:    ctx.Query("sort_by"),
-		SortOrder: ctx.Query("sort_order"),
+	queryParams, err := helpers.ParseListQuery(ctx.Request.URL.Query(), dto.PostListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	queryParams.ViewerID = currentViewerID(ctx)
+
+	postResponses, cursors, err := c.postService.GetAll(ctx.Request.Context(), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	var totalCount *int64
+	if wantsTotalCount(ctx) {
+		count, err := c.postService.Count(ctx.Request.Context(), queryParams)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		totalCount = &count
+	}
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponseCursorPagination(postResponses, totalCount, cursors))
+}
+
+// Publish godoc
+// @Summary Publish a post
+// @Description Publish a post now, or schedule it when publish_at is in the future
+// @Tags Posts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Param publishRequest body dto.PublishPostRequest false "Publication time"
+// @Success 200 {object} dto.BaseResponse{data=dto.PostResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Router /posts/{id}/publish [post]
+// @Security BearerAuth
+func (c *PostController) Publish(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		_ = ctx.Error(invalidID("post"))
+		return
 	}
 
-	// Optionally, convert page and pageSize to integers
-	page, _ := strconv.Atoi(ctx.Query("page"))
-	pageSize, _ := strconv.Atoi(ctx.Query("page_size"))
-	queryParams.Page = page
-	queryParams.PageSize = pageSize
+	var publishRequest dto.PublishPostRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&publishRequest); err != nil {
+			_ = ctx.Error(invalidPayload(err))
+			return
+		}
+	}
 
-	postResponses, err := c.postService.GetAll(&queryParams)
+	postResponse, err := c.postService.Publish(ctx.Request.Context(), uint(id), &publishRequest, currentActor(ctx))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, helpers.NewErrorResponse(http.StatusInternalServerError, "Failed to retrieve posts"))
+		_ = ctx.Error(err)
 		return
 	}
 
-	totalCount, err := c.postService.Count(&queryParams)
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(postResponse))
+}
+
+// Unpublish godoc
+// @Summary Unpublish a post
+// @Description Move a published or scheduled post back to draft
+// @Tags Posts
+// @Produce  json
+// @Param id path int true "Post ID"
+// @Success 200 {object} dto.BaseResponse{data=dto.PostResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Router /posts/{id}/unpublish [post]
+// @Security BearerAuth
+func (c *PostController) Unpublish(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, helpers.NewErrorResponse(http.StatusInternalServerError, "Failed to retrieve posts"))
+		_ = ctx.Error(invalidID("post"))
 		return
 	}
-	ctx.JSON(http.StatusOK, helpers.NewSuccessResponsePagination(postResponses, totalCount))
+
+	postResponse, err := c.postService.Unpublish(ctx.Request.Context(), uint(id), currentActor(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(postResponse))
 }
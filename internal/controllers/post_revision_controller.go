@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// PostRevisionController struct
+type PostRevisionController struct {
+	revisionService services.PostRevisionService
+}
+
+// NewPostRevisionController initializes post revision controller
+func NewPostRevisionController(revisionService services.PostRevisionService) *PostRevisionController {
+	return &PostRevisionController{revisionService: revisionService}
+}
+
+// GetAll godoc
+// @Summary List revisions of a post
+// @Description Retrieve every saved revision of a post, newest first
+// @Tags Post Revisions
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} dto.BaseResponse{data=[]dto.PostRevisionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Router /posts/{id}/revisions [get]
+// @Security BearerAuth
+func (c *PostRevisionController) GetAll(ctx *gin.Context) {
+	postID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || postID <= 0 {
+		_ = ctx.Error(invalidID("post"))
+		return
+	}
+
+	revisionResponses, err := c.revisionService.GetAll(ctx.Request.Context(), uint(postID), currentActor(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(revisionResponses))
+}
+
+// GetByRevision godoc
+// @Summary Get a revision of a post
+// @Description Retrieve a single revision of a post by its revision number
+// @Tags Post Revisions
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} dto.BaseResponse{data=dto.PostRevisionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Router /posts/{id}/revisions/{rev} [get]
+// @Security BearerAuth
+func (c *PostRevisionController) GetByRevision(ctx *gin.Context) {
+	postID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || postID <= 0 {
+		_ = ctx.Error(invalidID("post"))
+		return
+	}
+
+	revision, err := strconv.Atoi(ctx.Param("rev"))
+	if err != nil || revision <= 0 {
+		_ = ctx.Error(invalidRevision("rev"))
+		return
+	}
+
+	revisionResponse, err := c.revisionService.GetByRevision(ctx.Request.Context(), uint(postID), uint(revision), currentActor(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(revisionResponse))
+}
+
+// Diff godoc
+// @Summary Diff two revisions of a post
+// @Description Line-level diff of the title and content between two revisions
+// @Tags Post Revisions
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param from query int true "Base revision number"
+// @Param to query int true "Target revision number"
+// @Success 200 {object} dto.BaseResponse{data=dto.PostRevisionDiffResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Router /posts/{id}/revisions/diff [get]
+// @Security BearerAuth
+func (c *PostRevisionController) Diff(ctx *gin.Context) {
+	postID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || postID <= 0 {
+		_ = ctx.Error(invalidID("post"))
+		return
+	}
+
+	from, err := strconv.Atoi(ctx.Query("from"))
+	if err != nil || from <= 0 {
+		_ = ctx.Error(invalidRevision("from"))
+		return
+	}
+
+	to, err := strconv.Atoi(ctx.Query("to"))
+	if err != nil || to <= 0 {
+		_ = ctx.Error(invalidRevision("to"))
+		return
+	}
+
+	diffResponse, err := c.revisionService.Diff(ctx.Request.Context(), uint(postID), uint(from), uint(to), currentActor(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(diffResponse))
+}
+
+// Restore godoc
+// @Summary Restore a revision of a post
+// @Description Copy the title and content of a revision back onto the post, creating a new revision
+// @Tags Post Revisions
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} dto.BaseResponse{data=dto.PostResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Router /posts/{id}/revisions/{rev}/restore [post]
+// @Security BearerAuth
+func (c *PostRevisionController) Restore(ctx *gin.Context) {
+	postID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || postID <= 0 {
+		_ = ctx.Error(invalidID("post"))
+		return
+	}
+
+	revision, err := strconv.Atoi(ctx.Param("rev"))
+	if err != nil || revision <= 0 {
+		_ = ctx.Error(invalidRevision("rev"))
+		return
+	}
+
+	postResponse, err := c.revisionService.Restore(ctx.Request.Context(), uint(postID), uint(revision), currentActor(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(postResponse))
+}
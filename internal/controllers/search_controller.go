@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// SearchController struct
+type SearchController struct {
+	searchService services.SearchService
+}
+
+// NewSearchController initializes search controller
+func NewSearchController(searchService services.SearchService) *SearchController {
+	return &SearchController{searchService: searchService}
+}
+
+// Search godoc
+// @Summary Search posts
+// @Description Full-text search over published posts, ranked by relevance with title matches weighted above content matches. Supports "quoted phrases" and prefix* queries; every term must match.
+// @Tags Search
+// @Produce json
+// @Param q query string true "Search query"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.SearchResultResponse}}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /search [get]
+func (c *SearchController) Search(ctx *gin.Context) {
+	query := ctx.Query("q")
+
+	queryParams, err := helpers.ParseListQuery(ctx.Request.URL.Query(), dto.SearchListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	results, err := c.searchService.Search(ctx.Request.Context(), query, queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	totalCount, err := c.searchService.Count(ctx.Request.Context(), query, queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponsePagination(results, totalCount))
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// TagController struct
+type TagController struct {
+	tagService services.TagService
+}
+
+// NewTagController initializes tag controller
+func NewTagController(tagService services.TagService) *TagController {
+	return &TagController{tagService: tagService}
+}
+
+// GetAll godoc
+// @Summary Get tags
+// @Description Retrieve the tags used by published posts with their post counts, most used first
+// @Tags Tags
+// @Produce json
+// @Param search query string false "Search by tag name"
+// @Param sort query string false "post_count or name, prefix - for descending; default -post_count,name"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} dto.BaseResponse{data=dto.PaginationResponse{items=[]dto.TagResponse}}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /tags [get]
+func (c *TagController) GetAll(ctx *gin.Context) {
+	queryParams, err := helpers.ParseListQuery(ctx.Request.URL.Query(), dto.TagListSpec)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	tagResponses, err := c.tagService.GetAll(ctx.Request.Context(), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	totalCount, err := c.tagService.Count(ctx.Request.Context(), queryParams)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponsePagination(tagResponses, totalCount))
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"github.com/dedenfarhanhub/blog-service/internal/helpers"
 	"net/http"
+	"strconv"
 
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 	"github.com/dedenfarhanhub/blog-service/internal/services"
@@ -33,13 +34,13 @@ func NewUserController(userService services.UserService) *UserController {
 func (c *UserController) Register(ctx *gin.Context) {
 	var userDto dto.UserRequest
 	if err := ctx.ShouldBindJSON(&userDto); err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		_ = ctx.Error(invalidPayload(err))
 		return
 	}
 
-	userResponse, err := c.userService.Register(&userDto)
+	userResponse, err := c.userService.Register(ctx.Request.Context(), &userDto)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, helpers.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		_ = ctx.Error(err)
 		return
 	}
 
@@ -56,20 +57,184 @@ func (c *UserController) Register(ctx *gin.Context) {
 // @Success 200 {object} dto.BaseResponse{data=dto.UserResponse}
 // @Failure 400 {object} dto.BaseResponse
 // @Failure 401 {object} dto.BaseResponse
+// @Failure 429 {object} dto.BaseResponse
 // @Failure 500 {object} dto.BaseResponse
 // @Router /login [post]
 func (c *UserController) Login(ctx *gin.Context) {
 	var loginDto dto.UserLoginRequest
 	if err := ctx.ShouldBindJSON(&loginDto); err != nil {
-		ctx.JSON(http.StatusBadRequest, helpers.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		_ = ctx.Error(invalidPayload(err))
 		return
 	}
 
-	userResponse, err := c.userService.Login(&loginDto)
+	loginDto.ClientIP = ctx.ClientIP()
+
+	userResponse, err := c.userService.Login(ctx.Request.Context(), &loginDto)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, helpers.NewErrorResponse(http.StatusUnauthorized, err.Error()))
+		_ = ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(userResponse))
 }
+
+// UpdateRole godoc
+// @Summary Change a user's role
+// @Description Assign one of the roles admin, editor, author or reader to a user (admins only)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role body dto.UpdateRoleRequest true "New role"
+// @Success 200 {object} dto.BaseResponse{data=dto.UserRoleResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Router /users/{id}/role [put]
+// @Security BearerAuth
+func (c *UserController) UpdateRole(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || userID <= 0 {
+		_ = ctx.Error(invalidID("user"))
+		return
+	}
+
+	var roleDto dto.UpdateRoleRequest
+	if err := ctx.ShouldBindJSON(&roleDto); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	userRoleResponse, err := c.userService.UpdateRole(ctx.Request.Context(), uint(userID), roleDto.Role, currentActor(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(userRoleResponse))
+}
+
+// UnlockLogin godoc
+// @Summary Unlock a user's login
+// @Description Lift the lockout applied after repeated failed logins, before it runs out (admins only)
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Router /users/{id}/lockout [delete]
+// @Security BearerAuth
+func (c *UserController) UnlockLogin(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || userID <= 0 {
+		_ = ctx.Error(invalidID("user"))
+		return
+	}
+
+	if err := c.userService.UnlockLogin(ctx.Request.Context(), uint(userID), currentActor(ctx)); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
+
+// GetProfile godoc
+// @Summary Get your profile
+// @Description Get the account of the signed-in user, including an email change waiting for confirmation
+// @Tags Users
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=dto.ProfileResponse}
+// @Failure 404 {object} dto.BaseResponse
+// @Router /users/me [get]
+// @Security BearerAuth
+func (c *UserController) GetProfile(ctx *gin.Context) {
+	profileResponse, err := c.userService.GetProfile(ctx.Request.Context(), currentViewerID(ctx))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(profileResponse))
+}
+
+// UpdateProfile godoc
+// @Summary Update your profile
+// @Description Change the name, bio, avatar URL or website of the signed-in user; omitted fields are left as they are. A new email needs current_password and is only used once confirmed through the link mailed to it.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param profile body dto.UpdateProfileRequest true "Profile fields to change"
+// @Success 200 {object} dto.BaseResponse{data=dto.ProfileResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Router /users/me [patch]
+// @Security BearerAuth
+func (c *UserController) UpdateProfile(ctx *gin.Context) {
+	var profileDto dto.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&profileDto); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	profileResponse, err := c.userService.UpdateProfile(ctx.Request.Context(), currentViewerID(ctx), &profileDto)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(profileResponse))
+}
+
+// ChangePassword godoc
+// @Summary Change your password
+// @Description Replace the password of the signed-in user. Every session is signed out and new tokens are returned for this one.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param password body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} dto.BaseResponse{data=dto.TokenResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Router /users/me/password [post]
+// @Security BearerAuth
+func (c *UserController) ChangePassword(ctx *gin.Context) {
+	var passwordDto dto.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&passwordDto); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	tokenResponse, err := c.userService.ChangePassword(ctx.Request.Context(), currentViewerID(ctx), &passwordDto)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(tokenResponse))
+}
+
+// DeleteAccount godoc
+// @Summary Delete your account
+// @Description Delete the account of the signed-in user together with their posts, after checking the password. Comments stay without the account.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param account body dto.DeleteAccountRequest true "Current password"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Router /users/me [delete]
+// @Security BearerAuth
+func (c *UserController) DeleteAccount(ctx *gin.Context) {
+	var deleteDto dto.DeleteAccountRequest
+	if err := ctx.ShouldBindJSON(&deleteDto); err != nil {
+		_ = ctx.Error(invalidPayload(err))
+		return
+	}
+
+	claims := ctx.MustGet("claims").(*helpers.Claims)
+	if err := c.userService.DeleteAccount(ctx.Request.Context(), claims, deleteDto.Password); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.NewSuccessResponse(nil))
+}
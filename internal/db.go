@@ -2,6 +2,7 @@ package internal
 
 import (
 	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/metrics"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -11,11 +12,29 @@ func InitDB() (*gorm.DB, error) {
 	// Load configuration
 	cfg := config.LoadConfig()
 
-	// Initialize database connection
-	db, err := gorm.Open(mysql.Open(cfg.DBUser+":"+cfg.DBPassword+"@tcp("+cfg.DBHost+":"+cfg.DBPort+")/"+cfg.DBName+"?charset=utf8mb4&parseTime=True&loc=Local"), &gorm.Config{})
+	// Initialize database connection. Driver errors are translated, so a unique key violation is
+	// gorm.ErrDuplicatedKey and repositories can report it as a conflict.
+	db, err := gorm.Open(mysql.Open(dataSourceName(cfg, "")), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, err
 	}
 
+	// Time every query and export the connection pool statistics on /metrics
+	if err := metrics.InstrumentDB(db, cfg.DBName); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
+
+// InitMigrationDB initializes the connection migrations run on. Migration files hold several statements
+// and are sent in one go, which the driver only accepts with multiStatements; the server's own
+// connection keeps it off.
+func InitMigrationDB() (*gorm.DB, error) {
+	return gorm.Open(mysql.Open(dataSourceName(config.LoadConfig(), "&multiStatements=true")), &gorm.Config{})
+}
+
+// dataSourceName builds the MySQL DSN of the configured database, with extra parameters appended
+func dataSourceName(cfg *config.Config, params string) string {
+	return cfg.DBUser + ":" + cfg.DBPassword + "@tcp(" + cfg.DBHost + ":" + cfg.DBPort + ")/" + cfg.DBName + "?charset=utf8mb4&parseTime=True&loc=Local" + params
+}
@@ -0,0 +1,7 @@
+package dto
+
+// Actor identifies the authenticated user performing an action.
+type Actor struct {
+	ID   uint
+	Role string
+}
@@ -0,0 +1,13 @@
+package dto
+
+// AuditLogResponse struct
+type AuditLogResponse struct {
+	ID           uint   `json:"id"`
+	ActorID      uint   `json:"actor_id"`
+	ActorRole    string `json:"actor_role"`
+	Action       string `json:"action"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   uint   `json:"resource_id"`
+	Details      string `json:"details,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
@@ -1,15 +1,27 @@
 package dto
 
 // BaseResponse represents the standard structure for API responses.
+// Error responses carry a stable ErrorCode, and validation errors the invalid fields in Errors.
 type BaseResponse struct {
-	Code    int         `json:"code"`
-	Status  string      `json:"status"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Code      int          `json:"code"`
+	Status    string       `json:"status"`
+	Message   string       `json:"message"`
+	ErrorCode string       `json:"error_code,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	Data      interface{}  `json:"data,omitempty"`
+}
+
+// FieldError describes why a field of a request is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 // PaginationResponse represents the structure for paginated responses.
+// TotalCount is left out when the client skipped counting; the cursors are set for keyset-paginated lists.
 type PaginationResponse struct {
 	Items      interface{} `json:"items,omitempty"`
-	TotalCount int64       `json:"total_count"`
+	TotalCount *int64      `json:"total_count,omitempty"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
 }
@@ -4,13 +4,45 @@ package dto
 type CommentRequest struct {
 	AuthorName string `json:"author_name" binding:"required"`
 	Content    string `json:"content" binding:"required"`
+	ParentID   *uint  `json:"parent_id"`
+	// Website is a honeypot field: it is hidden from humans, so only bots fill it in
+	Website  string `json:"website"`
+	UserID   uint   `json:"-"`
+	ClientIP string `json:"-"`
 }
 
 // CommentResponse struct
 type CommentResponse struct {
 	ID         uint   `json:"id"`
 	PostID     uint   `json:"post_id"`
+	ParentID   *uint  `json:"parent_id,omitempty"`
+	Depth      int    `json:"depth"`
 	AuthorName string `json:"author_name"`
 	Content    string `json:"content"`
+	Status     string `json:"status"`
 	CreatedAt  string `json:"created_at"`
+	// SpamScore and SpamReasons are only exposed to moderators
+	SpamScore   *float64 `json:"spam_score,omitempty"`
+	SpamReasons []string `json:"spam_reasons,omitempty"`
+}
+
+// CommentTreeResponse is a comment together with its nested replies.
+type CommentTreeResponse struct {
+	*CommentResponse
+	// Hidden marks a reply the viewer cannot see, kept only as the parent of visible replies
+	Hidden          bool                   `json:"hidden,omitempty"`
+	ReplyCount      int                    `json:"reply_count"`
+	DescendantCount int                    `json:"descendant_count"`
+	Replies         []*CommentTreeResponse `json:"replies"`
+}
+
+// ModerateCommentsRequest changes the moderation status of several comments at once.
+type ModerateCommentsRequest struct {
+	CommentIDs []uint `json:"comment_ids" binding:"required,min=1,max=100"`
+	Status     string `json:"status" binding:"required,oneof=approved rejected spam pending"`
+}
+
+// ModerateCommentsResponse reports how many comments were moderated.
+type ModerateCommentsResponse struct {
+	Updated int64 `json:"updated"`
 }
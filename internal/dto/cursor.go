@@ -0,0 +1,17 @@
+package dto
+
+// Cursor is the decoded position in a keyset-paginated list: the sort key and ID of the row to continue
+// from. Sort names the ordering the cursor was issued for ("-created_at"), so it cannot be replayed
+// against another one, and Backward walks towards the previous page.
+type Cursor struct {
+	Sort     string `json:"s"`
+	Value    string `json:"v"`
+	ID       uint   `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// PageCursors are the opaque tokens of the next and previous pages, empty when there is no such page.
+type PageCursors struct {
+	Next string
+	Prev string
+}
@@ -0,0 +1,20 @@
+package dto
+
+// HealthResponse tells that the server is alive
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// DependencyCheck is the outcome of checking one dependency: up or down, with the reason when down
+type DependencyCheck struct {
+	Status     string  `json:"status"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// ReadinessResponse tells whether the server can take traffic (ready, unready or shutting_down), with
+// the check of each dependency
+type ReadinessResponse struct {
+	Status string                     `json:"status"`
+	Checks map[string]DependencyCheck `json:"checks"`
+}
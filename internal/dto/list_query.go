@@ -0,0 +1,200 @@
+package dto
+
+// ListQuery is a parsed and validated list request: paging, sorting and filters. Sort and Filters only
+// ever contain fields allowed by the resource's ListSpec, so their columns are safe to put into SQL.
+type ListQuery struct {
+	Search   string
+	Page     int
+	PageSize int
+	Sort     []SortField
+	Filters  []Filter
+	// Cursor is the opaque token of a keyset page; Keyset is its decoded form. KeysetSort is set
+	// when the list is sorted on a single column that supports keyset pagination.
+	Cursor     string
+	Keyset     *Cursor
+	KeysetSort bool
+	ViewerID   uint
+}
+
+// SortField is one column of a (possibly multi-column) ordering
+type SortField struct {
+	Field  string
+	Column string
+	Desc   bool
+}
+
+// FilterOperator compares a column with the values of a filter
+type FilterOperator string
+
+// Supported filter operators
+const (
+	OpEq  FilterOperator = "eq"
+	OpNe  FilterOperator = "ne"
+	OpGt  FilterOperator = "gt"
+	OpGte FilterOperator = "gte"
+	OpLt  FilterOperator = "lt"
+	OpLte FilterOperator = "lte"
+	OpIn  FilterOperator = "in"
+)
+
+// Filter is one condition of a list request. Values are already converted to the field's type;
+// operators other than OpIn have exactly one value.
+type Filter struct {
+	Field    string
+	Column   string
+	Operator FilterOperator
+	Values   []interface{}
+}
+
+// Filter returns the first filter on the given field, or nil
+func (q *ListQuery) Filter(field string) *Filter {
+	for i := range q.Filters {
+		if q.Filters[i].Field == field {
+			return &q.Filters[i]
+		}
+	}
+	return nil
+}
+
+// FieldType is the type the values of a filter are parsed into
+type FieldType int
+
+// Supported field types
+const (
+	// FieldString values are used as given
+	FieldString FieldType = iota
+	// FieldSlug values are slugified, matching how slugs are stored
+	FieldSlug
+	// FieldInt values are unsigned integers, e.g. IDs
+	FieldInt
+	// FieldFloat values are decimal numbers
+	FieldFloat
+	// FieldTime values are dates (2006-01-02) or RFC 3339 times
+	FieldTime
+)
+
+// SortableField is a field a list can be ordered by
+type SortableField struct {
+	Name   string
+	Column string
+	// Keyset marks columns that support cursor pagination
+	Keyset bool
+}
+
+// FilterField is a field a list can be filtered on. A field without a Column is applied by the
+// repository itself (e.g. through a join table).
+type FilterField struct {
+	Name      string
+	Column    string
+	Type      FieldType
+	Operators []FilterOperator
+	// Values restricts the field to a fixed set of values
+	Values []string
+}
+
+// ListSpec is the allow-list of a list endpoint: what it can be sorted and filtered by
+type ListSpec struct {
+	Sortable   []SortableField
+	Filterable []FilterField
+	// DefaultSort is used when the request does not ask for an ordering, e.g. "-created_at"
+	DefaultSort string
+	// Params are the further query parameters the endpoint reads itself
+	Params []string
+}
+
+var (
+	equalityOperators = []FilterOperator{OpEq, OpNe, OpIn}
+	rangeOperators    = []FilterOperator{OpEq, OpGt, OpGte, OpLt, OpLte}
+	postStatuses      = []string{"draft", "scheduled", "published", "archived"}
+	commentStatuses   = []string{"pending", "approved", "rejected", "spam"}
+)
+
+// PostListSpec is the allow-list of GET /posts
+var PostListSpec = &ListSpec{
+	Sortable: []SortableField{
+		{Name: "created_at", Column: "created_at", Keyset: true},
+		{Name: "updated_at", Column: "updated_at", Keyset: true},
+		{Name: "id", Column: "id", Keyset: true},
+		{Name: "published_at", Column: "published_at"},
+		{Name: "title", Column: "title"},
+	},
+	Filterable: []FilterField{
+		{Name: "status", Column: "status", Type: FieldString, Operators: equalityOperators, Values: postStatuses},
+		{Name: "content_format", Column: "content_format", Type: FieldString, Operators: equalityOperators, Values: []string{"markdown", "plain"}},
+		{Name: "author_id", Column: "author_id", Type: FieldInt, Operators: equalityOperators},
+		{Name: "tag", Type: FieldSlug, Operators: []FilterOperator{OpEq, OpIn}},
+		{Name: "category", Type: FieldSlug, Operators: []FilterOperator{OpEq, OpIn}},
+		{Name: "created_at", Column: "created_at", Type: FieldTime, Operators: rangeOperators},
+		{Name: "updated_at", Column: "updated_at", Type: FieldTime, Operators: rangeOperators},
+		{Name: "published_at", Column: "published_at", Type: FieldTime, Operators: rangeOperators},
+	},
+	DefaultSort: "-created_at",
+}
+
+// CommentListSpec is the allow-list of GET /posts/{id}/comments
+var CommentListSpec = &ListSpec{
+	Sortable: []SortableField{
+		{Name: "created_at", Column: "created_at", Keyset: true},
+		{Name: "id", Column: "id", Keyset: true},
+	},
+	Filterable: []FilterField{
+		{Name: "user_id", Column: "user_id", Type: FieldInt, Operators: equalityOperators},
+		{Name: "created_at", Column: "created_at", Type: FieldTime, Operators: rangeOperators},
+	},
+	DefaultSort: "created_at",
+	Params:      []string{"view"},
+}
+
+// ModerationListSpec is the allow-list of the comment moderation queue
+var ModerationListSpec = &ListSpec{
+	Sortable: []SortableField{
+		{Name: "created_at", Column: "created_at"},
+		{Name: "spam_score", Column: "spam_score"},
+		{Name: "id", Column: "id"},
+	},
+	Filterable: []FilterField{
+		{Name: "status", Column: "status", Type: FieldString, Operators: []FilterOperator{OpEq}, Values: commentStatuses},
+		{Name: "post_id", Column: "post_id", Type: FieldInt, Operators: equalityOperators},
+		{Name: "spam_score", Column: "spam_score", Type: FieldFloat, Operators: rangeOperators},
+		{Name: "created_at", Column: "created_at", Type: FieldTime, Operators: rangeOperators},
+	},
+	DefaultSort: "created_at",
+}
+
+// AuditLogListSpec is the allow-list of GET /audit-logs
+var AuditLogListSpec = &ListSpec{
+	Sortable: []SortableField{
+		{Name: "created_at", Column: "created_at"},
+		{Name: "id", Column: "id"},
+	},
+	Filterable: []FilterField{
+		{Name: "action", Column: "action", Type: FieldString, Operators: equalityOperators},
+		{Name: "resource_type", Column: "resource_type", Type: FieldString, Operators: equalityOperators},
+		{Name: "actor_id", Column: "actor_id", Type: FieldInt, Operators: equalityOperators},
+		{Name: "created_at", Column: "created_at", Type: FieldTime, Operators: rangeOperators},
+	},
+	DefaultSort: "-created_at",
+}
+
+// TagListSpec is the allow-list of GET /tags
+var TagListSpec = &ListSpec{
+	Sortable: []SortableField{
+		{Name: "post_count", Column: "post_count"},
+		{Name: "name", Column: "tags.name"},
+	},
+	DefaultSort: "-post_count,name",
+}
+
+// CategoryListSpec is the allow-list of GET /categories
+var CategoryListSpec = &ListSpec{
+	Sortable: []SortableField{
+		{Name: "post_count", Column: "post_count"},
+		{Name: "name", Column: "categories.name"},
+	},
+	DefaultSort: "-post_count,name",
+}
+
+// SearchListSpec is the allow-list of GET /search, which is always ordered by relevance
+var SearchListSpec = &ListSpec{
+	Params: []string{"q"},
+}
@@ -1,19 +1,49 @@
 package dto
 
+import "time"
+
 // PostRequest represents the request body for a post.
 type PostRequest struct {
-	Title    string `json:"title" binding:"required"`
-	Content  string `json:"content" binding:"required"`
-	AuthorID uint   `json:"author_id"`
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+	// ContentFormat is markdown (the default for new posts) or plain text
+	ContentFormat string     `json:"content_format" binding:"omitempty,oneof=markdown plain"`
+	AuthorID      uint       `json:"author_id"`
+	Status        string     `json:"status" binding:"omitempty,oneof=draft scheduled published archived"`
+	PublishAt     *time.Time `json:"publish_at"`
+	// CommentPolicy overrides the global comment moderation policy for this post
+	CommentPolicy string `json:"comment_policy" binding:"omitempty,oneof=inherit open moderated anonymous"`
+	// Tags are created on first use; Categories are slugs of existing categories.
+	// Leaving either out on update keeps the current ones, an empty list clears them.
+	Tags       []string `json:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+	Categories []string `json:"categories" binding:"omitempty,max=10,dive,required,max=100"`
+}
+
+// PublishPostRequest represents the request body for publishing a post now or at a later time.
+type PublishPostRequest struct {
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 // PostResponse represents the response body for a post.
+// ContentHTML is the content rendered to sanitized HTML, ContentMarkdown the source of markdown posts
+// and ReadingTime the estimated reading time in minutes.
 type PostResponse struct {
-	ID        uint            `json:"id"`
-	Title     string          `json:"title"`
-	Content   string          `json:"content"`
-	AuthorID  uint            `json:"author_id"`
-	Author    *AuthorResponse `json:"author"`
-	CreatedAt string          `json:"created_at"`
-	UpdatedAt string          `json:"updated_at"`
+	ID              uint                `json:"id"`
+	Title           string              `json:"title"`
+	Slug            string              `json:"slug"`
+	Content         string              `json:"content"`
+	ContentFormat   string              `json:"content_format"`
+	ContentHTML     string              `json:"content_html"`
+	ContentMarkdown string              `json:"content_markdown,omitempty"`
+	Excerpt         string              `json:"excerpt"`
+	ReadingTime     int                 `json:"reading_time"`
+	AuthorID        uint                `json:"author_id"`
+	Author          *AuthorResponse     `json:"author"`
+	Status          string              `json:"status"`
+	PublishedAt     string              `json:"published_at,omitempty"`
+	CommentPolicy   string              `json:"comment_policy"`
+	Tags            []*TagResponse      `json:"tags"`
+	Categories      []*CategoryResponse `json:"categories"`
+	CreatedAt       string              `json:"created_at"`
+	UpdatedAt       string              `json:"updated_at"`
 }
@@ -0,0 +1,30 @@
+package dto
+
+// PostRevisionResponse represents a single revision of a post.
+type PostRevisionResponse struct {
+	ID            uint   `json:"id"`
+	PostID        uint   `json:"post_id"`
+	Revision      uint   `json:"revision"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	EditorID      uint   `json:"editor_id"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// DiffLine represents one line of a line-level diff.
+type DiffLine struct {
+	Op      string `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+}
+
+// PostRevisionDiffResponse represents the differences between two revisions of a post.
+type PostRevisionDiffResponse struct {
+	PostID       uint        `json:"post_id"`
+	FromRevision uint        `json:"from_revision"`
+	ToRevision   uint        `json:"to_revision"`
+	Title        []*DiffLine `json:"title"`
+	Content      []*DiffLine `json:"content"`
+}
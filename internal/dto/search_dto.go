@@ -0,0 +1,14 @@
+package dto
+
+// SearchResultResponse represents a post matching a search query.
+// TitleHighlight and Snippet are HTML-escaped with the matching words wrapped in <mark> tags.
+type SearchResultResponse struct {
+	ID             uint    `json:"id"`
+	Title          string  `json:"title"`
+	Slug           string  `json:"slug"`
+	AuthorID       uint    `json:"author_id"`
+	PublishedAt    string  `json:"published_at,omitempty"`
+	Score          float64 `json:"score"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
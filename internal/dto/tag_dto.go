@@ -0,0 +1,24 @@
+package dto
+
+// TagResponse represents a tag. PostCount is only filled in by the tag listing.
+type TagResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	PostCount int64  `json:"post_count,omitempty"`
+}
+
+// CategoryRequest represents the request body for creating a category.
+type CategoryRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// Slug defaults to a slug of the name
+	Slug string `json:"slug" binding:"omitempty,max=100"`
+}
+
+// CategoryResponse represents a category. PostCount is only filled in by the category listing.
+type CategoryResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	PostCount int64  `json:"post_count,omitempty"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // UserRequest struct
 type UserRequest struct {
 	Name     string `json:"name" binding:"required"`
@@ -9,29 +11,101 @@ type UserRequest struct {
 
 // UserResponse struct
 type UserResponse struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Token string `json:"token"`
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+	Token         string `json:"token"`
+	RefreshToken  string `json:"refresh_token"`
+	ExpiresIn     int64  `json:"expires_in"`
 }
 
 // UserLoginRequest struct
 type UserLoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	// ClientIP is set by the controller, failed attempts are also counted per IP
+	ClientIP string `json:"-"`
 }
 
 // UserLoginResponse struct
 type UserLoginResponse struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Token string `json:"token"`
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+	Token         string `json:"token"`
+	RefreshToken  string `json:"refresh_token"`
+	ExpiresIn     int64  `json:"expires_in"`
 }
 
 // AuthorResponse represents the author's information in the post response.
 type AuthorResponse struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// AuthorProfileResponse is the public page of an author. The email is only shown to the author and to admins.
+type AuthorProfileResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	Bio       string    `json:"bio"`
+	AvatarURL string    `json:"avatar_url"`
+	Website   string    `json:"website"`
+	PostCount int64     `json:"post_count"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+// UpdateRoleRequest struct
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// UserRoleResponse struct
+type UserRoleResponse struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// ProfileResponse is the account of the signed-in user
+type ProfileResponse struct {
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	PendingEmail  string    `json:"pending_email,omitempty"`
+	EmailVerified bool      `json:"email_verified"`
+	Role          string    `json:"role"`
+	Bio           string    `json:"bio"`
+	AvatarURL     string    `json:"avatar_url"`
+	Website       string    `json:"website"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// UpdateProfileRequest struct. Omitted fields are left unchanged, and an empty bio, avatar_url or
+// website clears it. Changing the email needs the current password and only takes effect once the
+// new address is confirmed.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" binding:"omitempty,max=255"`
+	Bio             *string `json:"bio" binding:"omitempty,max=1000"`
+	AvatarURL       *string `json:"avatar_url" binding:"omitempty,max=2048"`
+	Website         *string `json:"website" binding:"omitempty,max=2048"`
+	Email           *string `json:"email" binding:"omitempty,max=255"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+// ChangePasswordRequest struct
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// DeleteAccountRequest struct
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
 }
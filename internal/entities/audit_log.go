@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"time"
+)
+
+// AuditLog records a privileged action performed by a user.
+type AuditLog struct {
+	ID           uint   `gorm:"primaryKey"`
+	ActorID      uint   `gorm:"not null"`
+	ActorRole    string `gorm:"not null"`
+	Action       string `gorm:"not null"`
+	ResourceType string `gorm:"not null"`
+	ResourceID   uint   `gorm:"not null"`
+	Details      string
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// ToAuditLogResponse converts an AuditLog entity to an AuditLogResponse DTO.
+func (a *AuditLog) ToAuditLogResponse() *dto.AuditLogResponse {
+	return &dto.AuditLogResponse{
+		ID:           a.ID,
+		ActorID:      a.ActorID,
+		ActorRole:    a.ActorRole,
+		Action:       a.Action,
+		ResourceType: a.ResourceType,
+		ResourceID:   a.ResourceID,
+		Details:      a.Details,
+		CreatedAt:    a.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -2,16 +2,35 @@ package entities
 
 import (
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"strings"
 	"time"
 )
 
-// Comment represents a comment on a blog post.
+// Comment moderation statuses
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+// Comment represents a comment on a blog post. Replies point to their parent and to the
+// top-level comment of their thread (RootID), and Depth is 0 for top-level comments.
 type Comment struct {
-	ID         uint      `gorm:"primaryKey"`
-	PostID     uint      `gorm:"not null"`
-	AuthorName string    `gorm:"not null"`
-	Content    string    `gorm:"not null"`
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	ID          uint `gorm:"primaryKey"`
+	PostID      uint `gorm:"not null"`
+	ParentID    *uint
+	RootID      *uint
+	Depth       int `gorm:"not null;default:0"`
+	UserID      *uint
+	AuthorName  string  `gorm:"not null"`
+	Content     string  `gorm:"not null"`
+	Status      string  `gorm:"not null;default:approved"`
+	SpamScore   float64 `gorm:"not null;default:0"`
+	SpamReasons string
+	ModeratedBy *uint
+	ModeratedAt *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
 
 	Post *Post `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
@@ -21,8 +40,53 @@ func (c *Comment) ToCommentResponse() *dto.CommentResponse {
 	return &dto.CommentResponse{
 		ID:         c.ID,
 		PostID:     c.PostID,
+		ParentID:   c.ParentID,
+		Depth:      c.Depth,
 		AuthorName: c.AuthorName,
 		Content:    c.Content,
+		Status:     c.Status,
 		CreatedAt:  c.CreatedAt.Format(time.RFC3339),
 	}
 }
+
+// ToHiddenCommentResponse converts a Comment entity to a CommentResponse DTO that only places the comment in its thread.
+func (c *Comment) ToHiddenCommentResponse() *dto.CommentResponse {
+	return &dto.CommentResponse{
+		ID:        c.ID,
+		PostID:    c.PostID,
+		ParentID:  c.ParentID,
+		Depth:     c.Depth,
+		CreatedAt: c.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ThreadRootID returns the ID of the top-level comment of the thread this comment belongs to
+func (c *Comment) ThreadRootID() uint {
+	if c.RootID != nil {
+		return *c.RootID
+	}
+	return c.ID
+}
+
+// IsValidCommentStatus reports whether the status is a known moderation status
+func IsValidCommentStatus(status string) bool {
+	switch status {
+	case CommentStatusPending, CommentStatusApproved, CommentStatusRejected, CommentStatusSpam:
+		return true
+	}
+	return false
+}
+
+// ToModerationResponse converts a Comment entity to a CommentResponse DTO including its spam analysis.
+func (c *Comment) ToModerationResponse() *dto.CommentResponse {
+	commentResponse := c.ToCommentResponse()
+	spamScore := c.SpamScore
+	commentResponse.SpamScore = &spamScore
+	if c.SpamReasons != "" {
+		commentResponse.SpamReasons = strings.Split(c.SpamReasons, SpamReasonSeparator)
+	}
+	return commentResponse
+}
+
+// SpamReasonSeparator separates the reasons stored in SpamReasons
+const SpamReasonSeparator = "; "
@@ -2,31 +2,131 @@ package entities
 
 import (
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"html"
 	"time"
 )
 
+// Post statuses
+const (
+	PostStatusDraft     = "draft"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+	PostStatusArchived  = "archived"
+)
+
+// postStatusTransitions lists the states a saved post may move to. A live post is taken down through
+// draft or archived, never by scheduling it again, and an archived post comes back as a draft or published.
+var postStatusTransitions = map[string][]string{
+	PostStatusDraft:     {PostStatusDraft, PostStatusScheduled, PostStatusPublished, PostStatusArchived},
+	PostStatusScheduled: {PostStatusDraft, PostStatusScheduled, PostStatusPublished},
+	PostStatusPublished: {PostStatusDraft, PostStatusPublished, PostStatusArchived},
+	PostStatusArchived:  {PostStatusDraft, PostStatusPublished, PostStatusArchived},
+}
+
+// Content formats
+const (
+	ContentFormatMarkdown = "markdown"
+	ContentFormatPlain    = "plain"
+)
+
+// Comment policies decide which new comments on a post wait for moderation
+const (
+	CommentPolicyInherit   = "inherit"
+	CommentPolicyOpen      = "open"
+	CommentPolicyModerated = "moderated"
+	CommentPolicyAnonymous = "anonymous"
+)
+
 // Post represents a blog post.
 type Post struct {
-	ID        uint      `gorm:"primaryKey"`
-	Title     string    `gorm:"not null"`
-	Content   string    `gorm:"not null"`
-	AuthorID  uint      `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID            uint   `gorm:"primaryKey"`
+	Title         string `gorm:"not null"`
+	Slug          string `gorm:"not null;uniqueIndex"`
+	Content       string `gorm:"not null"`
+	ContentFormat string `gorm:"not null;default:markdown"`
+	AuthorID      uint   `gorm:"not null"`
+	Status        string `gorm:"not null;default:published"`
+	PublishedAt   *time.Time
+	CommentPolicy string    `gorm:"not null;default:inherit"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
 
-	Author   *User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	Comments []*Comment `gorm:"foreignKey:PostID"`
+	Author     *User       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Comments   []*Comment  `gorm:"foreignKey:PostID"`
+	Tags       []*Tag      `gorm:"many2many:post_tags;"`
+	Categories []*Category `gorm:"many2many:post_categories;"`
 }
 
 // ToPostResponse converts a Post entity to a PostResponse DTO.
 func (p *Post) ToPostResponse(author *dto.AuthorResponse) *dto.PostResponse {
-	return &dto.PostResponse{
-		ID:        p.ID,
-		Title:     p.Title,
-		Content:   p.Content,
-		AuthorID:  p.AuthorID,
-		Author:    author,
-		CreatedAt: p.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	postResponse := &dto.PostResponse{
+		ID:            p.ID,
+		Title:         html.EscapeString(p.Title),
+		Slug:          p.Slug,
+		Content:       p.Content,
+		ContentFormat: p.ContentFormat,
+		AuthorID:      p.AuthorID,
+		Author:        author,
+		Status:        p.Status,
+		CommentPolicy: p.CommentPolicy,
+		Tags:          []*dto.TagResponse{},
+		Categories:    []*dto.CategoryResponse{},
+		CreatedAt:     p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     p.UpdatedAt.Format(time.RFC3339),
+	}
+	if p.ContentFormat == ContentFormatMarkdown {
+		postResponse.ContentMarkdown = p.Content
+	}
+	if p.PublishedAt != nil {
+		postResponse.PublishedAt = p.PublishedAt.Format(time.RFC3339)
+	}
+	for _, tag := range p.Tags {
+		postResponse.Tags = append(postResponse.Tags, tag.ToTagResponse())
+	}
+	for _, category := range p.Categories {
+		postResponse.Categories = append(postResponse.Categories, category.ToCategoryResponse())
+	}
+	return postResponse
+}
+
+// IsPublic reports whether the post is visible to everyone
+func (p *Post) IsPublic() bool {
+	return p.Status == PostStatusPublished
+}
+
+// IsPostStatus reports whether the status is one of the post statuses
+func IsPostStatus(status string) bool {
+	_, ok := postStatusTransitions[status]
+	return ok
+}
+
+// CanMoveTo reports whether the post may move to the given status. A new post can start in any status.
+func (p *Post) CanMoveTo(status string) bool {
+	if p.Status == "" {
+		return true
+	}
+	for _, allowed := range postStatusTransitions[p.Status] {
+		if allowed == status {
+			return true
+		}
 	}
+	return false
+}
+
+// IsVisibleTo reports whether the post can be read by the given user (0 for anonymous readers)
+func (p *Post) IsVisibleTo(userID uint) bool {
+	return p.IsPublic() || (userID != 0 && p.AuthorID == userID)
+}
+
+// PostSlugAlias is a previous slug of a post, kept so old links keep resolving after a title change.
+type PostSlugAlias struct {
+	Slug      string    `gorm:"primaryKey"`
+	PostID    uint      `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// PostSearchHit is a post ID matching a full-text search with its relevance score.
+type PostSearchHit struct {
+	ID    uint
+	Score float64
 }
@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"html"
+	"time"
+)
+
+// PostRevision is an immutable snapshot of a post's title, content and content format after a create or update.
+type PostRevision struct {
+	ID            uint      `gorm:"primaryKey"`
+	PostID        uint      `gorm:"not null"`
+	Revision      uint      `gorm:"not null"`
+	Title         string    `gorm:"not null"`
+	Content       string    `gorm:"not null"`
+	ContentFormat string    `gorm:"not null;default:markdown"`
+	EditorID      uint      `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+// ToPostRevisionResponse converts a PostRevision entity to a PostRevisionResponse DTO.
+func (r *PostRevision) ToPostRevisionResponse() *dto.PostRevisionResponse {
+	return &dto.PostRevisionResponse{
+		ID:            r.ID,
+		PostID:        r.PostID,
+		Revision:      r.Revision,
+		Title:         html.EscapeString(r.Title),
+		Content:       r.Content,
+		ContentFormat: r.ContentFormat,
+		EditorID:      r.EditorID,
+		CreatedAt:     r.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// RefreshToken represents a hashed, single-use refresh token. Tokens issued from the
+// same login share a FamilyID so that a reused token can revoke the whole chain.
+type RefreshToken struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"not null"`
+	FamilyID     string    `gorm:"not null"`
+	TokenHash    string    `gorm:"unique;not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	RevokedAt    *time.Time
+	ReplacedByID *uint
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// IsExpired reports whether the refresh token is past its expiry
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
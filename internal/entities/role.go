@@ -0,0 +1,74 @@
+package entities
+
+// Role is the authorization role assigned to a user.
+type Role string
+
+// Permission is a single action a role may be allowed to perform.
+type Permission string
+
+// Available roles
+const (
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleAuthor Role = "author"
+	RoleReader Role = "reader"
+)
+
+// DefaultRole is assigned to newly registered users
+const DefaultRole = RoleAuthor
+
+// Available permissions
+const (
+	PermissionPostCreate      Permission = "post:create"
+	PermissionPostUpdateAny   Permission = "post:update:any"
+	PermissionPostDeleteAny   Permission = "post:delete:any"
+	PermissionCommentModerate Permission = "comment:moderate"
+	PermissionUserManage      Permission = "user:manage"
+	PermissionAuditRead       Permission = "audit:read"
+	PermissionCategoryManage  Permission = "category:manage"
+)
+
+// rolePermissions maps every role to the permissions it grants
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionPostCreate,
+		PermissionPostUpdateAny,
+		PermissionPostDeleteAny,
+		PermissionCommentModerate,
+		PermissionUserManage,
+		PermissionAuditRead,
+		PermissionCategoryManage,
+	},
+	RoleEditor: {
+		PermissionPostCreate,
+		PermissionPostUpdateAny,
+		PermissionPostDeleteAny,
+		PermissionCommentModerate,
+		PermissionCategoryManage,
+	},
+	RoleAuthor: {
+		PermissionPostCreate,
+	},
+	RoleReader: {},
+}
+
+// IsValid reports whether the role is one of the known roles
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// HasPermission reports whether the role grants the given permission
+func (r Role) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions returns the permissions granted by the role
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// SpamDocumentsToken is the reserved row holding the number of spam and ham documents trained.
+// The tokenizer only produces letters and digits, so it can never collide with a real token.
+const SpamDocumentsToken = "__documents__"
+
+// SpamToken holds how often a token appeared in comments moderated as spam and as ham.
+type SpamToken struct {
+	Token     string    `gorm:"primaryKey"`
+	SpamCount int       `gorm:"not null;default:0"`
+	HamCount  int       `gorm:"not null;default:0"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
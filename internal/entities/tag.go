@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"time"
+)
+
+// Tag is a free-form label attached to posts.
+type Tag struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"not null"`
+	Slug      string    `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// Category is a curated grouping of posts managed by editors.
+type Category struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"not null"`
+	Slug      string    `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TaxonomyPostCount is a tag or category together with the number of published posts using it.
+type TaxonomyPostCount struct {
+	ID        uint
+	Name      string
+	Slug      string
+	PostCount int64
+}
+
+// ToTagResponse converts a Tag entity to a TagResponse DTO.
+func (t *Tag) ToTagResponse() *dto.TagResponse {
+	return &dto.TagResponse{
+		ID:   t.ID,
+		Name: t.Name,
+		Slug: t.Slug,
+	}
+}
+
+// ToCategoryResponse converts a Category entity to a CategoryResponse DTO.
+func (c *Category) ToCategoryResponse() *dto.CategoryResponse {
+	return &dto.CategoryResponse{
+		ID:   c.ID,
+		Name: c.Name,
+		Slug: c.Slug,
+	}
+}
+
+// ToTagResponse converts a counted tag to a TagResponse DTO.
+func (t *TaxonomyPostCount) ToTagResponse() *dto.TagResponse {
+	return &dto.TagResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Slug:      t.Slug,
+		PostCount: t.PostCount,
+	}
+}
+
+// ToCategoryResponse converts a counted category to a CategoryResponse DTO.
+func (t *TaxonomyPostCount) ToCategoryResponse() *dto.CategoryResponse {
+	return &dto.CategoryResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Slug:      t.Slug,
+		PostCount: t.PostCount,
+	}
+}
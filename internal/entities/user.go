@@ -7,41 +7,121 @@ import (
 
 // User struct model
 type User struct {
-	ID           uint      `gorm:"primaryKey"`
-	Name         string    `gorm:"not null"`
-	Email        string    `gorm:"unique;not null"`
-	PasswordHash string    `gorm:"not null"`
-	CreatedAt    time.Time `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+	ID           uint   `gorm:"primaryKey"`
+	Name         string `gorm:"not null"`
+	Email        string `gorm:"unique;not null"`
+	PasswordHash string `gorm:"not null"`
+	Role         string `gorm:"not null;default:author"`
+	Bio          string `gorm:"not null;default:''"`
+	AvatarURL    string `gorm:"not null;default:''"`
+	Website      string `gorm:"not null;default:''"`
+	// PendingEmail is the address the user asked to change to, until they confirm it
+	PendingEmail *string
+	// EmailVerifiedAt is set once the user followed the link mailed to their address
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
 
 	Posts []*Post `gorm:"foreignKey:AuthorID"`
 }
 
 // ToUserResponse convert User to UserResponse
-func (u *User) ToUserResponse(token string) *dto.UserResponse {
+func (u *User) ToUserResponse(tokens *dto.TokenResponse) *dto.UserResponse {
 	return &dto.UserResponse{
-		ID:    u.ID,
-		Name:  u.Name,
-		Email: u.Email,
-		Token: token,
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		Role:          u.Role,
+		EmailVerified: u.IsEmailVerified(),
+		Token:         tokens.AccessToken,
+		RefreshToken:  tokens.RefreshToken,
+		ExpiresIn:     tokens.ExpiresIn,
 	}
 }
 
 // ToUserLoginResponse convert User to UserLoginResponse
-func (u *User) ToUserLoginResponse(token string) *dto.UserLoginResponse {
+func (u *User) ToUserLoginResponse(tokens *dto.TokenResponse) *dto.UserLoginResponse {
 	return &dto.UserLoginResponse{
-		ID:    u.ID,
-		Name:  u.Name,
-		Email: u.Email,
-		Token: token,
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		Role:          u.Role,
+		EmailVerified: u.IsEmailVerified(),
+		Token:         tokens.AccessToken,
+		RefreshToken:  tokens.RefreshToken,
+		ExpiresIn:     tokens.ExpiresIn,
 	}
 }
 
 // ToAuthorResponse convert User to AuthorResponse
 func (u *User) ToAuthorResponse() *dto.AuthorResponse {
 	return &dto.AuthorResponse{
-		ID:    u.ID,
-		Name:  u.Name,
-		Email: u.Email,
+		ID:        u.ID,
+		Name:      u.Name,
+		AvatarURL: u.AvatarURL,
 	}
 }
+
+// ToAuthorProfileResponse convert User to AuthorProfileResponse. The email is only included when
+// the viewer is allowed to see it.
+func (u *User) ToAuthorProfileResponse(postCount int64, withEmail bool) *dto.AuthorProfileResponse {
+	profile := &dto.AuthorProfileResponse{
+		ID:        u.ID,
+		Name:      u.Name,
+		Bio:       u.Bio,
+		AvatarURL: u.AvatarURL,
+		Website:   u.Website,
+		PostCount: postCount,
+		JoinedAt:  u.CreatedAt,
+	}
+	if withEmail {
+		profile.Email = u.Email
+	}
+	return profile
+}
+
+// ToProfileResponse convert User to ProfileResponse
+func (u *User) ToProfileResponse() *dto.ProfileResponse {
+	profile := &dto.ProfileResponse{
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		EmailVerified: u.IsEmailVerified(),
+		Role:          string(u.GetRole()),
+		Bio:           u.Bio,
+		AvatarURL:     u.AvatarURL,
+		Website:       u.Website,
+		CreatedAt:     u.CreatedAt,
+	}
+	if u.PendingEmail != nil {
+		profile.PendingEmail = *u.PendingEmail
+	}
+	return profile
+}
+
+// ToUserRoleResponse convert User to UserRoleResponse
+func (u *User) ToUserRoleResponse() *dto.UserRoleResponse {
+	permissions := make([]string, 0)
+	for _, permission := range u.GetRole().Permissions() {
+		permissions = append(permissions, string(permission))
+	}
+	return &dto.UserRoleResponse{
+		ID:          u.ID,
+		Name:        u.Name,
+		Role:        string(u.GetRole()),
+		Permissions: permissions,
+	}
+}
+
+// GetRole returns the user's role, falling back to the default role for legacy rows
+func (u *User) GetRole() Role {
+	if u.Role == "" {
+		return DefaultRole
+	}
+	return Role(u.Role)
+}
+
+// IsEmailVerified reports whether the user proved they own their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
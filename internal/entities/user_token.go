@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// User token purposes
+const (
+	UserTokenPurposePasswordReset     = "password_reset"
+	UserTokenPurposeEmailVerification = "email_verification"
+	UserTokenPurposeEmailChange       = "email_change"
+)
+
+// UserToken is a hashed, single-use token mailed to a user to prove they own an email address,
+// either to reset their password, to verify their address or to confirm a new one
+type UserToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null"`
+	Purpose   string    `gorm:"not null"`
+	TokenHash string    `gorm:"unique;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// IsUsable reports whether the token has been neither used nor let expire
+func (t *UserToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
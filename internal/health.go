@@ -0,0 +1,17 @@
+package internal
+
+import (
+	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"gorm.io/gorm"
+)
+
+// InitHealth initializes the readiness checks, which expect the schema at the latest migration of this build
+func InitHealth(db *gorm.DB, redisService *services.RedisService) (services.HealthService, error) {
+	migrationVersion, err := LatestMigrationVersion()
+	if err != nil {
+		return nil, err
+	}
+	return services.NewHealthService(repositories.NewHealthRepository(db), redisService, migrationVersion, config.LoadConfig().HealthCheckTimeout), nil
+}
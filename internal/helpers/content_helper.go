@@ -0,0 +1,111 @@
+package helpers
+
+import (
+	"bytes"
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+const (
+	// excerptLength is the maximum number of characters in a generated excerpt
+	excerptLength = 200
+	// wordsPerMinute is the reading speed used to estimate reading time
+	wordsPerMinute = 200
+)
+
+// RenderedContent is post content rendered to sanitized HTML, with the derived plain-text fields
+type RenderedContent struct {
+	HTML        string `json:"html"`
+	Excerpt     string `json:"excerpt"`
+	ReadingTime int    `json:"reading_time"`
+}
+
+// markdownRenderer converts CommonMark with the GitHub extensions (tables, strikethrough, autolinks, task lists).
+// Raw HTML in the source is not rendered.
+var markdownRenderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// contentPolicy is the allow-list applied to every rendered document
+var contentPolicy = newContentPolicy()
+
+// tagPattern matches HTML tags when turning rendered HTML back into text
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+func newContentPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	// Keep the language hint on fenced code blocks for client-side highlighting
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[a-zA-Z0-9_+-]+$`)).OnElements("code")
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	return policy
+}
+
+// RenderMarkdown renders Markdown to sanitized HTML and derives its excerpt and reading time
+func RenderMarkdown(content string) (*RenderedContent, error) {
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert([]byte(content), &buf); err != nil {
+		return nil, err
+	}
+	return newRenderedContent(buf.String()), nil
+}
+
+// RenderPlainText renders plain text to HTML paragraphs and derives its excerpt and reading time
+func RenderPlainText(content string) *RenderedContent {
+	return newRenderedContent(renderPlainText(content))
+}
+
+// newRenderedContent sanitizes the rendered HTML against the allow-list and derives the plain-text fields
+func newRenderedContent(rendered string) *RenderedContent {
+	safeHTML := contentPolicy.Sanitize(rendered)
+	text := HTMLToText(safeHTML)
+
+	return &RenderedContent{
+		HTML:        safeHTML,
+		Excerpt:     excerpt(text, excerptLength),
+		ReadingTime: readingTime(text),
+	}
+}
+
+// renderPlainText turns blank-line separated paragraphs into HTML paragraphs, keeping line breaks
+func renderPlainText(content string) string {
+	var builder strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		builder.WriteString("<p>")
+		builder.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		builder.WriteString("</p>\n")
+	}
+	return builder.String()
+}
+
+// HTMLToText strips the tags of sanitized HTML and collapses whitespace
+func HTMLToText(document string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(tagPattern.ReplaceAllString(document, " "))), " ")
+}
+
+// excerpt cuts text to at most maxLength characters at a word boundary
+func excerpt(text string, maxLength int) string {
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+	runes := []rune(text)[:maxLength]
+	cut := string(runes)
+	if space := strings.LastIndexByte(cut, ' '); space > 0 {
+		cut = cut[:space]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// readingTime estimates the reading time in minutes, at least one minute
+func readingTime(text string) int {
+	words := len(strings.Fields(text))
+	return int(math.Max(1, math.Ceil(float64(words)/wordsPerMinute)))
+}
@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"strings"
+)
+
+// ErrInvalidCursor is returned for cursors that were tampered with, are malformed or belong to another ordering
+var ErrInvalidCursor = apperrors.Validation("invalid_cursor", "invalid cursor")
+
+// EncodeCursor serializes a cursor into an opaque token signed with HMAC-SHA256
+func EncodeCursor(cursor *dto.Cursor, secret []byte) string {
+	payload, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(encoded, secret))
+}
+
+// DecodeCursor verifies the signature of a cursor token and deserializes it
+func DecodeCursor(token string, secret []byte) (*dto.Cursor, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, signCursor(encoded, secret)) {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor dto.Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// signCursor computes the HMAC of the encoded cursor payload
+func signCursor(encoded string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,57 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+var testCursorSecret = []byte("cursor secret")
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursors := []*dto.Cursor{
+		{Sort: "-created_at", Value: "2024-01-02T03:04:05.123456789Z", ID: 42},
+		{Sort: "id", ID: 7, Backward: true},
+	}
+	for _, cursor := range cursors {
+		decoded, err := DecodeCursor(EncodeCursor(cursor, testCursorSecret), testCursorSecret)
+		if err != nil {
+			t.Fatalf("DecodeCursor failed for %+v: %v", cursor, err)
+		}
+		if !reflect.DeepEqual(decoded, cursor) {
+			t.Errorf("decoded %+v, want %+v", decoded, cursor)
+		}
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	token := EncodeCursor(&dto.Cursor{Sort: "-created_at", Value: "2024-01-02T03:04:05Z", ID: 42}, testCursorSecret)
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"-created_at","v":"2024-01-02T03:04:05Z","id":1}`))
+	unsignedJSON := base64.RawURLEncoding.EncodeToString([]byte("not json"))
+
+	tests := []struct {
+		name   string
+		token  string
+		secret []byte
+	}{
+		{"empty", "", testCursorSecret},
+		{"no signature", encoded, testCursorSecret},
+		{"other secret", token, []byte("other secret")},
+		{"swapped payload", forged + "." + signature, testCursorSecret},
+		{"signature not base64", encoded + ".!!!", testCursorSecret},
+		{"truncated signature", encoded + "." + signature[:len(signature)-2], testCursorSecret},
+		{"payload not json", unsignedJSON + "." + base64.RawURLEncoding.EncodeToString(signCursor(unsignedJSON, testCursorSecret)), testCursorSecret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.token, tt.secret); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) = %v, want ErrInvalidCursor", tt.token, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"strings"
+)
+
+// Diff operations
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffCells bounds the size of the LCS table; larger inputs fall back to a replace-all diff
+const maxDiffCells = 4_000_000
+
+// LineDiff returns a line-level diff that turns oldText into newText
+func LineDiff(oldText string, newText string) []*dto.DiffLine {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	// Lines shared at the start and the end do not need the LCS table
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]*dto.DiffLine, 0, len(oldLines)+len(newLines))
+	for i := 0; i < prefix; i++ {
+		diff = append(diff, &dto.DiffLine{Op: DiffEqual, Text: oldLines[i], OldLine: i + 1, NewLine: i + 1})
+	}
+
+	diff = append(diff, diffMiddle(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix], prefix, prefix)...)
+
+	for i := suffix; i > 0; i-- {
+		oldIndex := len(oldLines) - i
+		newIndex := len(newLines) - i
+		diff = append(diff, &dto.DiffLine{Op: DiffEqual, Text: oldLines[oldIndex], OldLine: oldIndex + 1, NewLine: newIndex + 1})
+	}
+
+	return diff
+}
+
+// diffMiddle diffs two line slices using a longest common subsequence table
+func diffMiddle(oldLines []string, newLines []string, oldOffset int, newOffset int) []*dto.DiffLine {
+	n, m := len(oldLines), len(newLines)
+	diff := make([]*dto.DiffLine, 0, n+m)
+
+	if n*m > maxDiffCells {
+		for i, line := range oldLines {
+			diff = append(diff, &dto.DiffLine{Op: DiffDelete, Text: line, OldLine: oldOffset + i + 1})
+		}
+		for j, line := range newLines {
+			diff = append(diff, &dto.DiffLine{Op: DiffInsert, Text: line, NewLine: newOffset + j + 1})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the LCS length of oldLines[i:] and newLines[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldLines[i] == newLines[j]:
+			diff = append(diff, &dto.DiffLine{Op: DiffEqual, Text: oldLines[i], OldLine: oldOffset + i + 1, NewLine: newOffset + j + 1})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] > lcs[i+1][j]):
+			diff = append(diff, &dto.DiffLine{Op: DiffInsert, Text: newLines[j], NewLine: newOffset + j + 1})
+			j++
+		default:
+			diff = append(diff, &dto.DiffLine{Op: DiffDelete, Text: oldLines[i], OldLine: oldOffset + i + 1})
+			i++
+		}
+	}
+
+	return diff
+}
+
+// splitLines splits text into lines, treating CRLF and LF alike
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -2,45 +2,144 @@ package helpers
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 )
 
-// Claims struct for payload JWT
+// Kinds of TokenError, to be matched with errors.Is
+var (
+	ErrTokenMalformed        = errors.New("token is malformed")
+	ErrTokenSignatureInvalid = errors.New("token signature is invalid")
+	ErrTokenExpired          = errors.New("token is expired")
+	ErrTokenInvalidClaims    = errors.New("token claims are invalid")
+)
+
+// TokenError is returned by TokenVerifier. It matches its Kind and its cause with errors.Is.
+type TokenError struct {
+	Kind error
+	Err  error
+}
+
+func (e *TokenError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the kind and the cause of the error
+func (e *TokenError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Claims struct for payload JWT. The token ID (jti) is RegisteredClaims.ID, as ID is the user ID.
 type Claims struct {
 	Email string `json:"email"`
 	ID    uint   `json:"id"`
-	jwt.StandardClaims
+	Role  string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// TokenIssuer signs access tokens
+type TokenIssuer interface {
+	GenerateToken(email string, id uint, role string, ttl time.Duration) (string, *Claims, error)
+}
+
+// TokenVerifier checks access tokens, failing with a TokenError of kind ErrTokenMalformed,
+// ErrTokenSignatureInvalid, ErrTokenExpired or ErrTokenInvalidClaims
+type TokenVerifier interface {
+	ValidateToken(tokenStr string) (*Claims, error)
 }
 
-// GenerateToken JWT
-func GenerateToken(email string, id uint, secret []byte) (string, error) {
-	claims := Claims{
+// GenerateToken JWT with a unique token ID (jti) so it can be revoked before it expires. The token
+// names its signing key in the kid header.
+func (k *JWTKeys) GenerateToken(email string, id uint, role string, ttl time.Duration) (string, *Claims, error) {
+	tokenID, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	claims := &Claims{
 		Email: email,
 		ID:    id,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
+		Role:  role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Issuer:    k.issuer,
+			Audience:  jwt.ClaimStrings{k.audience[0]},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secret)
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	if k.signing.id != "" {
+		token.Header["kid"] = k.signing.id
+	}
+	signed, err := token.SignedString(k.signing.signKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
-// ValidateToken validate and return claim
-func ValidateToken(tokenStr string, secret []byte) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return secret, nil
-	})
-
+// ValidateToken validate and return claim. The key is picked by the kid header, and the token must
+// use exactly that key's algorithm, so a token cannot choose how it is verified.
+func (k *JWTKeys) ValidateToken(tokenStr string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := k.verification[kid]
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return key.verifyKey, nil
+	},
+		jwt.WithValidMethods(k.methods()),
+		jwt.WithIssuer(k.issuer),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(k.clockSkew),
+	)
 	if err != nil {
-		return nil, err
+		return nil, tokenError(err)
+	}
+
+	// The parser checks a single audience, any of the configured ones is accepted
+	for _, audience := range k.audience {
+		for _, tokenAudience := range claims.Audience {
+			if tokenAudience == audience {
+				return claims, nil
+			}
+		}
 	}
+	return nil, &TokenError{Kind: ErrTokenInvalidClaims, Err: jwt.ErrTokenInvalidAudience}
+}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+// methods lists the algorithms of the verification keys
+func (k *JWTKeys) methods() []string {
+	methods := make([]string, 0, len(k.verification))
+	for _, key := range k.verification {
+		methods = append(methods, key.method.Alg())
 	}
+	return methods
+}
 
-	return nil, errors.New("invalid token")
+// tokenError sorts a parser error into one of the kinds of TokenError. A token whose key is unknown
+// or whose algorithm does not match its key counts as a bad signature.
+func tokenError(err error) *TokenError {
+	switch {
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return &TokenError{Kind: ErrTokenMalformed, Err: err}
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return &TokenError{Kind: ErrTokenSignatureInvalid, Err: err}
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return &TokenError{Kind: ErrTokenExpired, Err: err}
+	default:
+		return &TokenError{Kind: ErrTokenInvalidClaims, Err: err}
+	}
 }
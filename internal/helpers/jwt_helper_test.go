@@ -0,0 +1,386 @@
+package helpers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testClockSkew = 30 * time.Second
+
+// writePEM writes a PEM block to a file in the test's temporary directory and returns its path
+func writePEM(t *testing.T, name string, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writePrivateKey writes a PKCS #8 private key file
+func writePrivateKey(t *testing.T, key interface{}) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writePEM(t, "private.pem", "PRIVATE KEY", der)
+}
+
+// testJWTConfig is the key configuration of the tests, signing with HS256 unless a key file is set
+func testJWTConfig() JWTKeyConfig {
+	return JWTKeyConfig{
+		Secret:    "test secret",
+		Issuer:    "blog-service",
+		Audience:  []string{"blog-api", "blog-admin"},
+		ClockSkew: testClockSkew,
+	}
+}
+
+func loadTestKeys(t *testing.T, cfg JWTKeyConfig) *JWTKeys {
+	t.Helper()
+	keys, err := LoadJWTKeys(cfg)
+	if err != nil {
+		t.Fatalf("LoadJWTKeys failed: %v", err)
+	}
+	return keys
+}
+
+// validClaims are the claims GenerateToken would issue now
+func validClaims(keys *JWTKeys) *Claims {
+	now := time.Now()
+	return &Claims{
+		Email: "alice@example.com",
+		ID:    1,
+		Role:  "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "token-id",
+			Issuer:    keys.issuer,
+			Audience:  jwt.ClaimStrings{keys.audience[0]},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+// signTestToken signs the claims with the method, key and kid given
+func signTestToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims *Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestGenerateAndValidateToken(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configs := map[string]JWTKeyConfig{"HS256": testJWTConfig()}
+	for name, key := range map[string]interface{}{"EdDSA": edKey, "RS256": rsaKey} {
+		cfg := testJWTConfig()
+		cfg.PrivateKeyFile = writePrivateKey(t, key)
+		configs[name] = cfg
+	}
+
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			keys := loadTestKeys(t, cfg)
+			token, issued, err := keys.GenerateToken("alice@example.com", 1, "admin", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims, err := keys.ValidateToken(token)
+			if err != nil {
+				t.Fatalf("ValidateToken failed: %v", err)
+			}
+			if claims.ID != 1 || claims.Email != "alice@example.com" || claims.Role != "admin" || claims.RegisteredClaims.ID != issued.RegisteredClaims.ID {
+				t.Errorf("claims = %+v, want %+v", claims, issued)
+			}
+		})
+	}
+}
+
+func TestValidateTokenErrors(t *testing.T) {
+	keys := loadTestKeys(t, testJWTConfig())
+	secret := []byte("test secret")
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		token  func() string
+		kind   error
+		reason error
+	}{
+		{
+			name:  "not a token",
+			token: func() string { return "not-a-token" },
+			kind:  ErrTokenMalformed,
+		},
+		{
+			name:  "not base64",
+			token: func() string { return "a.b.c" },
+			kind:  ErrTokenMalformed,
+		},
+		{
+			name: "other secret",
+			token: func() string {
+				return signTestToken(t, jwt.SigningMethodHS256, []byte("other secret"), "", validClaims(keys))
+			},
+			kind:   ErrTokenSignatureInvalid,
+			reason: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name: "unknown kid",
+			token: func() string {
+				return signTestToken(t, jwt.SigningMethodHS256, secret, "rotated-away", validClaims(keys))
+			},
+			kind:   ErrTokenSignatureInvalid,
+			reason: jwt.ErrTokenUnverifiable,
+		},
+		{
+			name: "other HMAC algorithm",
+			token: func() string {
+				return signTestToken(t, jwt.SigningMethodHS512, secret, "", validClaims(keys))
+			},
+			kind:   ErrTokenSignatureInvalid,
+			reason: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name: "unsigned",
+			token: func() string {
+				return signTestToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", validClaims(keys))
+			},
+			kind:   ErrTokenSignatureInvalid,
+			reason: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims(keys)
+				claims.IssuedAt = jwt.NewNumericDate(now.Add(-time.Hour))
+				claims.NotBefore = claims.IssuedAt
+				claims.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute))
+				return signTestToken(t, jwt.SigningMethodHS256, secret, "", claims)
+			},
+			kind:   ErrTokenExpired,
+			reason: jwt.ErrTokenExpired,
+		},
+		{
+			name: "without expiry",
+			token: func() string {
+				claims := validClaims(keys)
+				claims.ExpiresAt = nil
+				return signTestToken(t, jwt.SigningMethodHS256, secret, "", claims)
+			},
+			kind:   ErrTokenInvalidClaims,
+			reason: jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims(keys)
+				claims.Issuer = "other-service"
+				return signTestToken(t, jwt.SigningMethodHS256, secret, "", claims)
+			},
+			kind:   ErrTokenInvalidClaims,
+			reason: jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims(keys)
+				claims.Audience = jwt.ClaimStrings{"other-api"}
+				return signTestToken(t, jwt.SigningMethodHS256, secret, "", claims)
+			},
+			kind:   ErrTokenInvalidClaims,
+			reason: jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name: "without audience",
+			token: func() string {
+				claims := validClaims(keys)
+				claims.Audience = nil
+				return signTestToken(t, jwt.SigningMethodHS256, secret, "", claims)
+			},
+			kind:   ErrTokenInvalidClaims,
+			reason: jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name: "issued in the future",
+			token: func() string {
+				claims := validClaims(keys)
+				claims.IssuedAt = jwt.NewNumericDate(now.Add(time.Hour))
+				return signTestToken(t, jwt.SigningMethodHS256, secret, "", claims)
+			},
+			kind:   ErrTokenInvalidClaims,
+			reason: jwt.ErrTokenUsedBeforeIssued,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := keys.ValidateToken(tt.token())
+			if claims != nil {
+				t.Errorf("claims returned for a rejected token: %+v", claims)
+			}
+			var tokenErr *TokenError
+			if !errors.As(err, &tokenErr) {
+				t.Fatalf("ValidateToken = %v, want a TokenError", err)
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("ValidateToken = %v, want kind %v", err, tt.kind)
+			}
+			if tt.reason != nil && !errors.Is(err, tt.reason) {
+				t.Errorf("ValidateToken = %v, want cause %v", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestValidateTokenAcceptsAnyAudience(t *testing.T) {
+	keys := loadTestKeys(t, testJWTConfig())
+	claims := validClaims(keys)
+	claims.Audience = jwt.ClaimStrings{"other-api", "blog-admin"}
+	if _, err := keys.ValidateToken(signTestToken(t, jwt.SigningMethodHS256, []byte("test secret"), "", claims)); err != nil {
+		t.Errorf("a token for a configured audience was rejected: %v", err)
+	}
+}
+
+func TestValidateTokenLeeway(t *testing.T) {
+	keys := loadTestKeys(t, testJWTConfig())
+	// A margin keeps the cases clear of the seconds the test itself takes
+	const margin = 5 * time.Second
+
+	tests := []struct {
+		name  string
+		nbf   time.Duration
+		exp   time.Duration
+		valid bool
+		kind  error
+	}{
+		{name: "expired within the leeway", exp: -testClockSkew + margin, valid: true},
+		{name: "expired beyond the leeway", exp: -testClockSkew - margin, kind: ErrTokenExpired},
+		{name: "not yet valid within the leeway", nbf: testClockSkew - margin, exp: time.Minute, valid: true},
+		{name: "not yet valid beyond the leeway", nbf: testClockSkew + margin, exp: time.Minute, kind: ErrTokenInvalidClaims},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			claims := validClaims(keys)
+			claims.IssuedAt = jwt.NewNumericDate(now.Add(-time.Hour))
+			claims.NotBefore = jwt.NewNumericDate(now.Add(tt.nbf))
+			claims.ExpiresAt = jwt.NewNumericDate(now.Add(tt.exp))
+
+			_, err := keys.ValidateToken(signTestToken(t, jwt.SigningMethodHS256, []byte("test secret"), "", claims))
+			if tt.valid {
+				if err != nil {
+					t.Errorf("ValidateToken = %v, want a valid token", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("ValidateToken = %v, want kind %v", err, tt.kind)
+			}
+		})
+	}
+}
+
+func TestValidateTokenAlgorithmConfusion(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		key    interface{}
+		public interface{}
+	}{
+		{name: "RS256", key: rsaKey, public: &rsaKey.PublicKey},
+		{name: "EdDSA", key: edKey, public: edPublic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testJWTConfig()
+			cfg.Secret = ""
+			cfg.PrivateKeyFile = writePrivateKey(t, tt.key)
+			keys := loadTestKeys(t, cfg)
+
+			// An HS256 token keyed with the published public key, in the forms an attacker could find it
+			publicDER, err := x509.MarshalPKIXPublicKey(tt.public)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+			for _, secret := range [][]byte{publicPEM, publicDER} {
+				for _, kid := range []string{keys.signing.id, ""} {
+					token := signTestToken(t, jwt.SigningMethodHS256, secret, kid, validClaims(keys))
+					if _, err := keys.ValidateToken(token); !errors.Is(err, ErrTokenSignatureInvalid) {
+						t.Errorf("HS256 token with kid %q: ValidateToken = %v, want ErrTokenSignatureInvalid", kid, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTokenAfterRotation(t *testing.T) {
+	_, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldCfg := testJWTConfig()
+	oldCfg.PrivateKeyFile = writePrivateKey(t, oldKey)
+	oldToken, _, err := loadTestKeys(t, oldCfg).GenerateToken("alice@example.com", 1, "user", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The old key stays a verification key after the rotation
+	newCfg := testJWTConfig()
+	newCfg.PrivateKeyFile = writePrivateKey(t, newKey)
+	rotated := loadTestKeys(t, newCfg)
+	if _, err := rotated.ValidateToken(oldToken); !errors.Is(err, ErrTokenSignatureInvalid) {
+		t.Errorf("token of a dropped key: ValidateToken = %v, want ErrTokenSignatureInvalid", err)
+	}
+
+	newCfg.PublicKeyFiles = []string{oldCfg.PrivateKeyFile}
+	rotated = loadTestKeys(t, newCfg)
+	if _, err := rotated.ValidateToken(oldToken); err != nil {
+		t.Errorf("token of the previous key: ValidateToken = %v", err)
+	}
+}
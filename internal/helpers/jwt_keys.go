@@ -0,0 +1,205 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTKeyConfig describes where the keys of access tokens come from. With a private key file tokens are
+// signed with RS256 or EdDSA, depending on the key, otherwise with HS256 and the shared secret.
+type JWTKeyConfig struct {
+	Secret         string
+	PrivateKeyFile string
+	// KeyID is the kid of the signing key, defaulting to its RFC 7638 thumbprint
+	KeyID string
+	// PublicKeyFiles are extra verification keys, e.g. the previous signing key during a rotation
+	PublicKeyFiles []string
+	Issuer         string
+	Audience       []string
+	ClockSkew      time.Duration
+}
+
+// JWTKeys signs and verifies access tokens, implementing TokenIssuer and TokenVerifier. It is loaded once at startup.
+type JWTKeys struct {
+	signing      *jwtKey
+	verification map[string]*jwtKey
+	issuer       string
+	audience     []string
+	clockSkew    time.Duration
+}
+
+// jwtKey is a key with its kid and algorithm. Symmetric keys have no public part and are never published.
+type jwtKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	public    crypto.PublicKey
+}
+
+// LoadJWTKeys reads the signing key and the verification keys
+func LoadJWTKeys(cfg JWTKeyConfig) (*JWTKeys, error) {
+	keys := &JWTKeys{
+		verification: map[string]*jwtKey{},
+		issuer:       cfg.Issuer,
+		audience:     cfg.Audience,
+		clockSkew:    cfg.ClockSkew,
+	}
+	if len(keys.audience) == 0 {
+		return nil, errors.New("jwt: an audience is required")
+	}
+
+	if cfg.PrivateKeyFile == "" {
+		if cfg.Secret == "" {
+			return nil, errors.New("jwt: JWT_SECRET or JWT_PRIVATE_KEY_FILE must be set")
+		}
+		keys.signing = &jwtKey{id: cfg.KeyID, method: jwt.SigningMethodHS256, signKey: []byte(cfg.Secret), verifyKey: []byte(cfg.Secret)}
+	} else {
+		signing, err := loadPEMKey(cfg.PrivateKeyFile, true)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.KeyID != "" {
+			signing.id = cfg.KeyID
+		}
+		keys.signing = signing
+	}
+	keys.verification[keys.signing.id] = keys.signing
+
+	for _, path := range cfg.PublicKeyFiles {
+		key, err := loadPEMKey(path, false)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := keys.verification[key.id]; !exists {
+			keys.verification[key.id] = key
+		}
+	}
+	return keys, nil
+}
+
+// JWKS returns the public verification keys, signing key first
+func (k *JWTKeys) JWKS() *dto.JWKSResponse {
+	jwks := &dto.JWKSResponse{Keys: []dto.JWK{}}
+	if k.signing.public != nil {
+		jwks.Keys = append(jwks.Keys, toJWK(k.signing))
+	}
+	ids := make([]string, 0, len(k.verification))
+	for id := range k.verification {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if key := k.verification[id]; id != k.signing.id && key.public != nil {
+			jwks.Keys = append(jwks.Keys, toJWK(key))
+		}
+	}
+	return jwks
+}
+
+// loadPEMKey reads a PEM encoded RSA or Ed25519 key. A private key is required for signing; for
+// verification either a public or a private key will do.
+func loadPEMKey(path string, private bool) (*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: read key %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt: %s is not a PEM file", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwt: unsupported PEM block %q in %s", block.Type, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: parse key %s: %w", path, err)
+	}
+
+	key := &jwtKey{}
+	switch parsedKey := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.signKey, key.public = jwt.SigningMethodRS256, parsedKey, &parsedKey.PublicKey
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, parsedKey
+	case ed25519.PrivateKey:
+		key.method, key.signKey, key.public = jwt.SigningMethodEdDSA, parsedKey, parsedKey.Public()
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, parsedKey
+	default:
+		return nil, fmt.Errorf("jwt: %s holds neither an RSA nor an Ed25519 key", path)
+	}
+	if private && key.signKey == nil {
+		return nil, fmt.Errorf("jwt: %s holds no private key", path)
+	}
+	if rsaKey, ok := key.public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("jwt: the RSA key in %s is shorter than 2048 bits", path)
+	}
+
+	key.verifyKey = key.public
+	key.id = keyThumbprint(toJWK(key))
+	return key, nil
+}
+
+// toJWK converts the public part of a key to a JWK
+func toJWK(key *jwtKey) dto.JWK {
+	jwk := dto.JWK{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+	switch publicKey := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+	return jwk
+}
+
+// keyThumbprint is the RFC 7638 thumbprint of a key: the SHA-256 of its required members in
+// lexicographic order, so the same key always gets the same kid
+func keyThumbprint(jwk dto.JWK) string {
+	var members interface{}
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,183 @@
+package helpers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+func TestKeyThumbprint(t *testing.T) {
+	tests := []struct {
+		name string
+		jwk  dto.JWK
+		want string
+	}{
+		{
+			// RFC 7638, section 3.1
+			name: "RSA",
+			jwk: dto.JWK{
+				Kty: "RSA",
+				E:   "AQAB",
+				N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+				// Members other than the required ones do not change the thumbprint
+				Kid: "2011-04-29",
+				Alg: "RS256",
+			},
+			want: "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		},
+		{
+			// RFC 8037, appendix A.3
+			name: "Ed25519",
+			jwk:  dto.JWK{Kty: "OKP", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+			want: "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keyThumbprint(tt.jwk); got != tt.want {
+				t.Errorf("keyThumbprint = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadJWTKeysRejects(t *testing.T) {
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(edPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notPEM := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(notPEM, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cfg    func(cfg *JWTKeyConfig)
+		reason string
+	}{
+		{
+			name:   "no secret and no key",
+			cfg:    func(cfg *JWTKeyConfig) { cfg.Secret = "" },
+			reason: "must be set",
+		},
+		{
+			name:   "no audience",
+			cfg:    func(cfg *JWTKeyConfig) { cfg.Audience = nil },
+			reason: "audience is required",
+		},
+		{
+			name:   "short RSA key",
+			cfg:    func(cfg *JWTKeyConfig) { cfg.PrivateKeyFile = writePrivateKey(t, smallKey) },
+			reason: "shorter than 2048 bits",
+		},
+		{
+			name: "short RSA verification key",
+			cfg: func(cfg *JWTKeyConfig) {
+				cfg.PublicKeyFiles = []string{writePEM(t, "public.pem", "RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&smallKey.PublicKey))}
+			},
+			reason: "shorter than 2048 bits",
+		},
+		{
+			name:   "missing file",
+			cfg:    func(cfg *JWTKeyConfig) { cfg.PrivateKeyFile = filepath.Join(t.TempDir(), "missing.pem") },
+			reason: "read key",
+		},
+		{
+			name:   "not PEM",
+			cfg:    func(cfg *JWTKeyConfig) { cfg.PrivateKeyFile = notPEM },
+			reason: "not a PEM file",
+		},
+		{
+			name: "corrupt key",
+			cfg: func(cfg *JWTKeyConfig) {
+				cfg.PrivateKeyFile = writePEM(t, "corrupt.pem", "PRIVATE KEY", []byte("garbage"))
+			},
+			reason: "parse key",
+		},
+		{
+			name: "unsupported block",
+			cfg: func(cfg *JWTKeyConfig) {
+				cfg.PrivateKeyFile = writePEM(t, "cert.pem", "CERTIFICATE", []byte("garbage"))
+			},
+			reason: "unsupported PEM block",
+		},
+		{
+			name:   "public key to sign with",
+			cfg:    func(cfg *JWTKeyConfig) { cfg.PrivateKeyFile = writePEM(t, "public.pem", "PUBLIC KEY", publicDER) },
+			reason: "holds no private key",
+		},
+		{
+			name:   "corrupt verification key",
+			cfg:    func(cfg *JWTKeyConfig) { cfg.PublicKeyFiles = []string{notPEM} },
+			reason: "not a PEM file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testJWTConfig()
+			tt.cfg(&cfg)
+			keys, err := LoadJWTKeys(cfg)
+			if err == nil {
+				t.Fatalf("LoadJWTKeys = %+v, want an error", keys)
+			}
+			if !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("LoadJWTKeys = %v, want an error about %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Shared secrets are never published
+	if jwks := loadTestKeys(t, testJWTConfig()).JWKS(); len(jwks.Keys) != 0 {
+		t.Errorf("JWKS of an HS256 setup = %+v, want no keys", jwks.Keys)
+	}
+
+	cfg := testJWTConfig()
+	cfg.PrivateKeyFile = writePrivateKey(t, signingKey)
+	cfg.PublicKeyFiles = []string{writePEM(t, "previous.pem", "RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&previousKey.PublicKey))}
+	keys := loadTestKeys(t, cfg)
+
+	jwks := keys.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS = %+v, want the signing and the previous key", jwks.Keys)
+	}
+	signing, previous := jwks.Keys[0], jwks.Keys[1]
+	if signing.Kty != "OKP" || signing.Alg != "EdDSA" || signing.Kid != keys.signing.id {
+		t.Errorf("signing key = %+v", signing)
+	}
+	if previous.Kty != "RSA" || previous.Alg != "RS256" || previous.E != "AQAB" {
+		t.Errorf("previous key = %+v", previous)
+	}
+	for _, jwk := range jwks.Keys {
+		if jwk.Kid != keyThumbprint(jwk) {
+			t.Errorf("kid %q is not the thumbprint of its key", jwk.Kid)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxInValues caps the number of values of an "in" filter
+const maxInValues = 50
+
+// listParams are the query parameters every list endpoint understands
+var listParams = []string{"page", "page_size", "cursor", "with_count", "search", "sort", "sort_by", "sort_order"}
+
+// ParseListQuery validates the query string of a list endpoint against its spec. Sorting is given as
+// sort=-created_at,title (a leading "-" sorts descending) or the older sort_by/sort_order pair. Filters
+// are written as field=value, field[op]=value or field[in]=a,b, and time fields named x_at also accept
+// x_after (inclusive) and x_before (exclusive). Anything outside the spec is rejected with an error
+// listing the allowed values.
+func ParseListQuery(values url.Values, spec *dto.ListSpec) (*dto.ListQuery, error) {
+	listQuery, err := parseListQuery(values, spec)
+	if err != nil {
+		return nil, apperrors.Validation("invalid_query", err.Error())
+	}
+	return listQuery, nil
+}
+
+// parseListQuery does the work of ParseListQuery
+func parseListQuery(values url.Values, spec *dto.ListSpec) (*dto.ListQuery, error) {
+	listQuery := &dto.ListQuery{
+		Search: values.Get("search"),
+		Cursor: values.Get("cursor"),
+	}
+
+	var err error
+	if listQuery.Page, err = parseListInt(values, "page"); err != nil {
+		return nil, err
+	}
+	if listQuery.PageSize, err = parseListInt(values, "page_size"); err != nil {
+		return nil, err
+	}
+
+	sortExpr := values.Get("sort")
+	if sortExpr == "" && values.Get("sort_by") != "" {
+		if sortExpr, err = legacySortExpr(values.Get("sort_by"), values.Get("sort_order")); err != nil {
+			return nil, err
+		}
+	}
+	if sortExpr != "" {
+		if listQuery.Sort, err = ParseSort(spec, sortExpr); err != nil {
+			return nil, err
+		}
+	}
+
+	// Walk the parameters in a stable order so errors are reproducible
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if containsString(listParams, key) || containsString(spec.Params, key) {
+			continue
+		}
+
+		field, operator, err := resolveFilterKey(spec, key)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range values[key] {
+			filter, err := parseFilter(field, operator, raw)
+			if err != nil {
+				return nil, err
+			}
+			listQuery.Filters = append(listQuery.Filters, *filter)
+		}
+	}
+
+	return listQuery, nil
+}
+
+// ParseSort parses a comma separated ordering such as "-created_at,title" against the spec
+func ParseSort(spec *dto.ListSpec, expr string) ([]dto.SortField, error) {
+	var fields []dto.SortField
+	for _, item := range strings.Split(expr, ",") {
+		item = strings.TrimSpace(item)
+		name := strings.TrimPrefix(item, "-")
+
+		sortable := findSortableField(spec, name)
+		if sortable == nil {
+			return nil, fmt.Errorf("invalid sort field %q, allowed: %s", name, strings.Join(sortableNames(spec), ", "))
+		}
+		for _, field := range fields {
+			if field.Field == name {
+				return nil, fmt.Errorf("sort field %q is given more than once", name)
+			}
+		}
+		fields = append(fields, dto.SortField{Field: name, Column: sortable.Column, Desc: strings.HasPrefix(item, "-")})
+	}
+	return fields, nil
+}
+
+// FormatSort is the inverse of ParseSort
+func FormatSort(fields []dto.SortField) string {
+	items := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.Desc {
+			items = append(items, "-"+field.Field)
+		} else {
+			items = append(items, field.Field)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// findSortableField returns the sortable field of the spec with the given name, or nil
+func findSortableField(spec *dto.ListSpec, name string) *dto.SortableField {
+	for i := range spec.Sortable {
+		if spec.Sortable[i].Name == name {
+			return &spec.Sortable[i]
+		}
+	}
+	return nil
+}
+
+// IsKeysetSort reports whether an ordering supports cursor pagination: a single keyset column
+func IsKeysetSort(spec *dto.ListSpec, fields []dto.SortField) bool {
+	if len(fields) != 1 {
+		return false
+	}
+	sortable := findSortableField(spec, fields[0].Field)
+	return sortable != nil && sortable.Keyset
+}
+
+// legacySortExpr turns sort_by/sort_order into a sort expression
+func legacySortExpr(sortBy string, sortOrder string) (string, error) {
+	var prefix string
+	switch sortOrder {
+	case "", "asc":
+	case "desc":
+		prefix = "-"
+	default:
+		return "", fmt.Errorf("invalid sort_order %q, allowed: asc, desc", sortOrder)
+	}
+
+	items := strings.Split(sortBy, ",")
+	for i, item := range items {
+		items[i] = prefix + strings.TrimSpace(item)
+	}
+	return strings.Join(items, ","), nil
+}
+
+// resolveFilterKey finds the field and operator named by a filter parameter
+func resolveFilterKey(spec *dto.ListSpec, key string) (*dto.FilterField, dto.FilterOperator, error) {
+	name, operator := key, dto.OpEq
+	if open := strings.IndexByte(key, '['); open > 0 && strings.HasSuffix(key, "]") {
+		name, operator = key[:open], dto.FilterOperator(key[open+1:len(key)-1])
+	} else if prefix, found := strings.CutSuffix(key, "_after"); found {
+		name, operator = prefix+"_at", dto.OpGte
+	} else if prefix, found := strings.CutSuffix(key, "_before"); found {
+		name, operator = prefix+"_at", dto.OpLt
+	}
+
+	var field *dto.FilterField
+	for i := range spec.Filterable {
+		if spec.Filterable[i].Name == name {
+			field = &spec.Filterable[i]
+			break
+		}
+	}
+	if field == nil {
+		return nil, "", fmt.Errorf("unknown query parameter %q, allowed filters: %s", key, strings.Join(filterNames(spec), ", "))
+	}
+
+	for _, allowed := range field.Operators {
+		if allowed == operator {
+			return field, operator, nil
+		}
+	}
+	allowed := make([]string, 0, len(field.Operators))
+	for _, op := range field.Operators {
+		allowed = append(allowed, string(op))
+	}
+	return nil, "", fmt.Errorf("operator %q is not supported for %s, allowed: %s", operator, name, strings.Join(allowed, ", "))
+}
+
+// parseFilter converts the raw value of a filter parameter to the field's type
+func parseFilter(field *dto.FilterField, operator dto.FilterOperator, raw string) (*dto.Filter, error) {
+	rawValues := []string{raw}
+	if operator == dto.OpIn {
+		rawValues = strings.Split(raw, ",")
+		if len(rawValues) > maxInValues {
+			return nil, fmt.Errorf("too many values for %s, at most %d are allowed", field.Name, maxInValues)
+		}
+	}
+
+	filter := &dto.Filter{Field: field.Name, Column: field.Column, Operator: operator}
+	for _, rawValue := range rawValues {
+		value, err := parseFilterValue(field, strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, err
+		}
+		filter.Values = append(filter.Values, value)
+	}
+	return filter, nil
+}
+
+// parseFilterValue converts a single filter value
+func parseFilterValue(field *dto.FilterField, raw string) (interface{}, error) {
+	if len(field.Values) > 0 && !containsString(field.Values, raw) {
+		return nil, fmt.Errorf("invalid value %q for %s, allowed: %s", raw, field.Name, strings.Join(field.Values, ", "))
+	}
+
+	switch field.Type {
+	case dto.FieldSlug:
+		slug := Slugify(raw)
+		if slug == "" {
+			return nil, fmt.Errorf("invalid value %q for %s, expected a slug", raw, field.Name)
+		}
+		return slug, nil
+	case dto.FieldInt:
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s, expected a positive integer", raw, field.Name)
+		}
+		return uint(value), nil
+	case dto.FieldFloat:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s, expected a number", raw, field.Name)
+		}
+		return value, nil
+	case dto.FieldTime:
+		if value, err := time.Parse(time.RFC3339, raw); err == nil {
+			return value, nil
+		}
+		value, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s, expected a date (2006-01-02) or an RFC 3339 time", raw, field.Name)
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
+
+// parseListInt reads an optional non-negative integer parameter
+func parseListInt(values url.Values, key string) (int, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a positive integer", key, raw)
+	}
+	return value, nil
+}
+
+// sortableNames lists the sortable fields of a spec
+func sortableNames(spec *dto.ListSpec) []string {
+	names := make([]string, 0, len(spec.Sortable))
+	for _, field := range spec.Sortable {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// filterNames lists the filter parameters of a spec, including the date range shorthands
+func filterNames(spec *dto.ListSpec) []string {
+	names := make([]string, 0, len(spec.Filterable))
+	for _, field := range spec.Filterable {
+		names = append(names, field.Name)
+		if prefix, found := strings.CutSuffix(field.Name, "_at"); found && field.Type == dto.FieldTime {
+			names = append(names, prefix+"_after", prefix+"_before")
+		}
+	}
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
+}
+
+// containsString reports whether the list contains the value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package helpers
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+// testListSpec is a small allow-list covering every field type
+var testListSpec = &dto.ListSpec{
+	Sortable: []dto.SortableField{
+		{Name: "created_at", Column: "created_at", Keyset: true},
+		{Name: "id", Column: "id", Keyset: true},
+		{Name: "title", Column: "title"},
+	},
+	Filterable: []dto.FilterField{
+		{Name: "status", Column: "status", Type: dto.FieldString, Operators: []dto.FilterOperator{dto.OpEq, dto.OpNe, dto.OpIn}, Values: []string{"draft", "published"}},
+		{Name: "author_id", Column: "author_id", Type: dto.FieldInt, Operators: []dto.FilterOperator{dto.OpEq, dto.OpIn}},
+		{Name: "tag", Type: dto.FieldSlug, Operators: []dto.FilterOperator{dto.OpEq, dto.OpIn}},
+		{Name: "score", Column: "score", Type: dto.FieldFloat, Operators: []dto.FilterOperator{dto.OpGte}},
+		{Name: "created_at", Column: "created_at", Type: dto.FieldTime, Operators: []dto.FilterOperator{dto.OpEq, dto.OpGte, dto.OpLt}},
+	},
+	DefaultSort: "-created_at",
+	Params:      []string{"view"},
+}
+
+func TestParseListQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		page    int
+		sort    string
+		filters []dto.Filter
+	}{
+		{name: "empty"},
+		{name: "paging and own params", query: "page=2&page_size=20&view=tree&with_count=false", page: 2},
+		{name: "sort", query: "sort=-created_at,title", sort: "-created_at,title"},
+		{name: "legacy sort", query: "sort_by=title&sort_order=desc", sort: "-title"},
+		{name: "legacy sort ascending by default", query: "sort_by=title,id", sort: "title,id"},
+		{name: "sort wins over legacy sort", query: "sort=id&sort_by=title&sort_order=desc", sort: "id"},
+		{
+			name:    "equality",
+			query:   "status=draft",
+			filters: []dto.Filter{{Field: "status", Column: "status", Operator: dto.OpEq, Values: []interface{}{"draft"}}},
+		},
+		{
+			name:    "operator",
+			query:   "status[ne]=draft",
+			filters: []dto.Filter{{Field: "status", Column: "status", Operator: dto.OpNe, Values: []interface{}{"draft"}}},
+		},
+		{
+			name:    "in",
+			query:   "author_id[in]=1, 2,3",
+			filters: []dto.Filter{{Field: "author_id", Column: "author_id", Operator: dto.OpIn, Values: []interface{}{uint(1), uint(2), uint(3)}}},
+		},
+		{
+			name:    "slugs are slugified",
+			query:   "tag=Go Lang",
+			filters: []dto.Filter{{Field: "tag", Operator: dto.OpEq, Values: []interface{}{"go-lang"}}},
+		},
+		{
+			name:    "float",
+			query:   "score[gte]=0.5",
+			filters: []dto.Filter{{Field: "score", Column: "score", Operator: dto.OpGte, Values: []interface{}{0.5}}},
+		},
+		{
+			name:  "after and before",
+			query: "created_after=2024-01-01&created_before=2024-02-01T12:00:00Z",
+			filters: []dto.Filter{
+				{Field: "created_at", Column: "created_at", Operator: dto.OpGte, Values: []interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}},
+				{Field: "created_at", Column: "created_at", Operator: dto.OpLt, Values: []interface{}{time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)}},
+			},
+		},
+		{
+			name:  "repeated parameter",
+			query: "status=draft&status=published",
+			filters: []dto.Filter{
+				{Field: "status", Column: "status", Operator: dto.OpEq, Values: []interface{}{"draft"}},
+				{Field: "status", Column: "status", Operator: dto.OpEq, Values: []interface{}{"published"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			listQuery, err := ParseListQuery(values, testListSpec)
+			if err != nil {
+				t.Fatalf("ParseListQuery(%q) failed: %v", tt.query, err)
+			}
+			if listQuery.Page != tt.page {
+				t.Errorf("page = %d, want %d", listQuery.Page, tt.page)
+			}
+			if sort := FormatSort(listQuery.Sort); sort != tt.sort {
+				t.Errorf("sort = %q, want %q", sort, tt.sort)
+			}
+			if !reflect.DeepEqual(listQuery.Filters, tt.filters) {
+				t.Errorf("filters = %+v, want %+v", listQuery.Filters, tt.filters)
+			}
+		})
+	}
+}
+
+func TestParseListQueryRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{"unknown sort field", "sort=password_hash", `invalid sort field "password_hash", allowed: created_at, id, title`},
+		{"duplicate sort field", "sort=id,-id", `sort field "id" is given more than once`},
+		{"legacy unknown sort field", "sort_by=email", `invalid sort field "email", allowed: created_at, id, title`},
+		{"legacy sort order", "sort_by=title&sort_order=sideways", `invalid sort_order "sideways", allowed: asc, desc`},
+		{"unknown filter", "email=a@b.c", `unknown query parameter "email", allowed filters: status, author_id, tag, score, created_at, created_after, created_before`},
+		{"unknown range shorthand", "updated_after=2024-01-01", `unknown query parameter "updated_after"`},
+		{"operator not allowed", "status[gt]=draft", `operator "gt" is not supported for status, allowed: eq, ne, in`},
+		{"shorthand operator not allowed", "score_after=1", `unknown query parameter "score_after"`},
+		{"value not allowed", "status=deleted", `invalid value "deleted" for status, allowed: draft, published`},
+		{"value in list not allowed", "status[in]=draft,deleted", `invalid value "deleted" for status`},
+		{"integer", "author_id=-1", `invalid value "-1" for author_id, expected a positive integer`},
+		{"number", "score[gte]=high", `invalid value "high" for score, expected a number`},
+		{"time", "created_after=yesterday", `invalid value "yesterday" for created_at, expected a date`},
+		{"slug", "tag=!!!", `invalid value "!!!" for tag, expected a slug`},
+		{"page", "page=-2", `invalid page "-2", expected a positive integer`},
+		{"page size", "page_size=ten", `invalid page_size "ten", expected a positive integer`},
+		{"too many in values", "author_id[in]=" + strings.Repeat("1,", maxInValues) + "1", "too many values for author_id, at most 50 are allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = ParseListQuery(values, testListSpec)
+			if err == nil {
+				t.Fatalf("ParseListQuery(%q) succeeded, want an error", tt.query)
+			}
+			if !errors.Is(err, apperrors.ErrValidation) {
+				t.Errorf("error %v is not a validation error", err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("error = %q, want it to contain %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestParseListQueryInLimit(t *testing.T) {
+	ids := strings.TrimSuffix(strings.Repeat("1,", maxInValues), ",")
+	listQuery, err := ParseListQuery(url.Values{"author_id[in]": {ids}}, testListSpec)
+	if err != nil {
+		t.Fatalf("%d values were rejected: %v", maxInValues, err)
+	}
+	if got := len(listQuery.Filters[0].Values); got != maxInValues {
+		t.Errorf("got %d values, want %d", got, maxInValues)
+	}
+}
+
+func TestParseSortRoundTrip(t *testing.T) {
+	for _, expr := range []string{"id", "-created_at", "title,-id"} {
+		fields, err := ParseSort(testListSpec, expr)
+		if err != nil {
+			t.Fatalf("ParseSort(%q) failed: %v", expr, err)
+		}
+		if got := FormatSort(fields); got != expr {
+			t.Errorf("FormatSort(ParseSort(%q)) = %q", expr, got)
+		}
+	}
+}
+
+func TestIsKeysetSort(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"created_at", true},
+		{"-id", true},
+		{"title", false},
+		{"created_at,id", false},
+	}
+	for _, tt := range tests {
+		fields, err := ParseSort(testListSpec, tt.expr)
+		if err != nil {
+			t.Fatalf("ParseSort(%q) failed: %v", tt.expr, err)
+		}
+		if got := IsKeysetSort(testListSpec, fields); got != tt.want {
+			t.Errorf("IsKeysetSort(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
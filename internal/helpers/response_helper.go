@@ -12,6 +12,18 @@ func NewErrorResponse(code int, message string) *dto.BaseResponse {
 	}
 }
 
+// NewCodedErrorResponse creates a new BaseResponse for errors with a machine-readable code, and the
+// invalid fields of a validation error.
+func NewCodedErrorResponse(code int, errorCode string, message string, fields []dto.FieldError) *dto.BaseResponse {
+	return &dto.BaseResponse{
+		Code:      code,
+		Status:    "ERROR",
+		Message:   message,
+		ErrorCode: errorCode,
+		Errors:    fields,
+	}
+}
+
 // NewSuccessResponse creates a new BaseResponse for successful responses.
 func NewSuccessResponse(data interface{}) *dto.BaseResponse {
 	return &dto.BaseResponse{
@@ -30,7 +42,26 @@ func NewSuccessResponsePagination(item interface{}, totalCount int64) *dto.BaseR
 		Message: "SUCCESS",
 		Data: dto.PaginationResponse{
 			Items:      item,
-			TotalCount: totalCount,
+			TotalCount: &totalCount,
 		},
 	}
 }
+
+// NewSuccessResponseCursorPagination creates a new BaseResponse for keyset-paginated responses.
+// A nil totalCount leaves the count out.
+func NewSuccessResponseCursorPagination(item interface{}, totalCount *int64, cursors *dto.PageCursors) *dto.BaseResponse {
+	pagination := dto.PaginationResponse{
+		Items:      item,
+		TotalCount: totalCount,
+	}
+	if cursors != nil {
+		pagination.NextCursor = cursors.Next
+		pagination.PrevCursor = cursors.Prev
+	}
+	return &dto.BaseResponse{
+		Code:    200,
+		Status:  "SUCCESS",
+		Message: "SUCCESS",
+		Data:    pagination,
+	}
+}
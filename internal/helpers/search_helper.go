@@ -0,0 +1,202 @@
+package helpers
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxSearchTerms bounds the number of terms taken from a search query
+	maxSearchTerms = 10
+	// snippetLength is the approximate number of characters in a search snippet
+	snippetLength = 160
+)
+
+// SearchTerm is a single word, a prefix ("go*") or a quoted phrase ("go modules") of a search query
+type SearchTerm struct {
+	Words  []string
+	Prefix bool
+}
+
+// IsPhrase reports whether the term is a phrase of several words
+func (t SearchTerm) IsPhrase() bool {
+	return len(t.Words) > 1
+}
+
+// WordToken is a word of a text with its byte offsets
+type WordToken struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// TokenizeWords splits text into lowercase words of letters and digits, keeping their positions
+func TokenizeWords(text string) []WordToken {
+	var tokens []WordToken
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r) || (start >= 0 && unicode.IsMark(r))
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			tokens = append(tokens, WordToken{Text: strings.ToLower(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, WordToken{Text: strings.ToLower(text[start:]), Start: start, End: len(text)})
+	}
+	return tokens
+}
+
+// ParseSearchQuery parses a query into terms: quoted text is a phrase, a trailing * makes a prefix query,
+// everything else is a word. Operators and punctuation are ignored.
+func ParseSearchQuery(query string) []SearchTerm {
+	var terms []SearchTerm
+	for i, part := range strings.Split(query, `"`) {
+		// Odd parts are inside quotes
+		if i%2 == 1 {
+			if words := tokenTexts(TokenizeWords(part)); len(words) > 0 {
+				terms = append(terms, SearchTerm{Words: words})
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			prefix := strings.HasSuffix(field, "*")
+			for _, word := range tokenTexts(TokenizeWords(field)) {
+				terms = append(terms, SearchTerm{Words: []string{word}})
+			}
+			if prefix && len(terms) > 0 && !terms[len(terms)-1].IsPhrase() {
+				terms[len(terms)-1].Prefix = true
+			}
+		}
+	}
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	return terms
+}
+
+// BooleanFullTextQuery builds a MySQL boolean-mode FULLTEXT query requiring every term.
+// Terms only contain letters and digits, so no user input reaches the query operators.
+func BooleanFullTextQuery(terms []SearchTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		switch {
+		case term.IsPhrase():
+			parts = append(parts, `+"`+strings.Join(term.Words, " ")+`"`)
+		case term.Prefix:
+			parts = append(parts, "+"+term.Words[0]+"*")
+		default:
+			parts = append(parts, "+"+term.Words[0])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Highlight HTML-escapes text and wraps the words matching the terms in <mark> tags
+func Highlight(text string, terms []SearchTerm) string {
+	return highlightRange(text, 0, len(text), matchRanges(TokenizeWords(text), terms))
+}
+
+// Snippet returns an HTML-escaped extract of the text around the first match, with the matches highlighted
+func Snippet(text string, terms []SearchTerm) string {
+	text = strings.Join(strings.Fields(text), " ")
+	tokens := TokenizeWords(text)
+	ranges := matchRanges(tokens, terms)
+
+	start := 0
+	if len(ranges) > 0 {
+		// Start a few words before the first match
+		start = ranges[0][0]
+		for i := len(tokens) - 1; i >= 0; i-- {
+			if tokens[i].Start < ranges[0][0] && utf8.RuneCountInString(text[tokens[i].Start:ranges[0][0]]) <= snippetLength/4 {
+				start = tokens[i].Start
+			}
+		}
+	}
+
+	end := len(text)
+	if utf8.RuneCountInString(text[start:]) > snippetLength {
+		end = start
+		for i := 0; i < snippetLength && end < len(text); i++ {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		}
+		if space := strings.LastIndexByte(text[start:end], ' '); space > 0 {
+			end = start + space
+		}
+	}
+
+	snippet := highlightRange(text, start, end, ranges)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// matchRanges returns the byte ranges of the tokens matching any term, in order
+func matchRanges(tokens []WordToken, terms []SearchTerm) [][2]int {
+	var ranges [][2]int
+	for i := 0; i < len(tokens); i++ {
+		for _, term := range terms {
+			if length := matchAt(tokens, i, term); length > 0 {
+				ranges = append(ranges, [2]int{tokens[i].Start, tokens[i+length-1].End})
+				i += length - 1
+				break
+			}
+		}
+	}
+	return ranges
+}
+
+// matchAt returns how many tokens starting at position i match the term, or 0
+func matchAt(tokens []WordToken, i int, term SearchTerm) int {
+	if i+len(term.Words) > len(tokens) {
+		return 0
+	}
+	for j, word := range term.Words {
+		token := tokens[i+j].Text
+		if term.Prefix && j == len(term.Words)-1 {
+			if !strings.HasPrefix(token, word) {
+				return 0
+			}
+		} else if token != word {
+			return 0
+		}
+	}
+	return len(term.Words)
+}
+
+// highlightRange escapes text[start:end] and marks the ranges falling inside it
+func highlightRange(text string, start int, end int, ranges [][2]int) string {
+	var builder strings.Builder
+	position := start
+	for _, r := range ranges {
+		if r[0] < position || r[1] > end {
+			continue
+		}
+		builder.WriteString(html.EscapeString(text[position:r[0]]))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(text[r[0]:r[1]]))
+		builder.WriteString("</mark>")
+		position = r[1]
+	}
+	builder.WriteString(html.EscapeString(text[position:end]))
+	return builder.String()
+}
+
+// tokenTexts returns the text of the tokens
+func tokenTexts(tokens []WordToken) []string {
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		words = append(words, token.Text)
+	}
+	return words
+}
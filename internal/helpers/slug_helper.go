@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength is the maximum number of letters and digits kept in a slug
+const maxSlugLength = 80
+
+// Slugify turns a name into a lowercase slug of letters and digits separated by single hyphens.
+// Letters of every script are kept; accents are only dropped from Latin letters ("Café" becomes "cafe"),
+// since other scripts need their combining marks. Long names are cut at a word boundary.
+func Slugify(name string) string {
+	var builder strings.Builder
+	pendingHyphen := false
+	latinBase := false
+	truncated := false
+	length := 0
+
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.IsMark(r):
+			if latinBase || builder.Len() == 0 {
+				continue
+			}
+			builder.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if length >= maxSlugLength {
+				truncated = true
+				break
+			}
+			if pendingHyphen && builder.Len() > 0 {
+				builder.WriteByte('-')
+				length++
+			}
+			pendingHyphen = false
+			latinBase = unicode.Is(unicode.Latin, r)
+			builder.WriteRune(r)
+			length++
+		default:
+			pendingHyphen = true
+			latinBase = false
+		}
+		if truncated {
+			break
+		}
+	}
+
+	slug := builder.String()
+	if truncated {
+		if cut := strings.LastIndexByte(slug, '-'); cut > 0 {
+			slug = slug[:cut]
+		}
+	}
+	return norm.NFC.String(slug)
+}
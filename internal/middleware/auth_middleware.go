@@ -1,35 +1,104 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
+	"strings"
 
-	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
 	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware check user
-func AuthMiddleware() gin.HandlerFunc {
+var (
+	errMissingToken = apperrors.Unauthorized("missing_token", "authorization header is missing")
+	errInvalidToken = apperrors.Unauthorized("invalid_token", "invalid token")
+	errExpiredToken = apperrors.Unauthorized("token_expired", "token has expired")
+	errRevokedToken = apperrors.Unauthorized("token_revoked", "token has been revoked")
+)
+
+// AuthMiddleware check user. A user already identified by OptionalAuthMiddleware is not checked again.
+func AuthMiddleware(redisService *services.RedisService, tokenVerifier helpers.TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString := c.GetHeader("Authorization")
-		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is missing"})
-			c.Abort()
+		if _, authenticated := c.Get("claims"); authenticated {
+			c.Next()
 			return
 		}
 
-		cfg := config.LoadConfig()
-		claims, err := helpers.ValidateToken(tokenString, []byte(cfg.JWTSecret))
+		claims, err := authenticate(c, redisService, tokenVerifier)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			if errors.Is(err, errMissingToken) {
+				c.Header("WWW-Authenticate", `Bearer`)
+			} else if errors.Is(err, apperrors.ErrUnauthorized) {
+				// Tell clients whether to refresh the token or to sign in again
+				c.Header("WWW-Authenticate", `Bearer error="invalid_token", error_description="`+err.Error()+`"`)
+			}
+			_ = c.Error(err)
 			c.Abort()
 			return
 		}
 
-		// Set user ID and email to context
-		c.Set("userID", claims.ID)
-		c.Set("userEmail", claims.Email)
+		setClaims(c, claims)
+		c.Next()
+	}
+}
 
+// OptionalAuthMiddleware identifies the user when a valid token is sent, but lets anonymous requests through
+func OptionalAuthMiddleware(redisService *services.RedisService, tokenVerifier helpers.TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, authenticated := c.Get("claims"); authenticated {
+			c.Next()
+			return
+		}
+		if claims, err := authenticate(c, redisService, tokenVerifier); err == nil {
+			setClaims(c, claims)
+		}
 		c.Next()
 	}
 }
+
+// authenticate validates the token of the request and checks it against the revocation list
+func authenticate(c *gin.Context, redisService *services.RedisService, tokenVerifier helpers.TokenVerifier) (*helpers.Claims, error) {
+	tokenString := bearerToken(c.GetHeader("Authorization"))
+	if tokenString == "" {
+		return nil, errMissingToken
+	}
+
+	claims, err := tokenVerifier.ValidateToken(tokenString)
+	if errors.Is(err, helpers.ErrTokenExpired) {
+		return nil, errExpiredToken
+	}
+	if err != nil {
+		return nil, errInvalidToken
+	}
+
+	// Reject tokens that were revoked on logout
+	revoked, err := redisService.Exists(c.Request.Context(), services.RevokedTokenEntity, claims.RegisteredClaims.ID)
+	if err != nil {
+		return nil, apperrors.Unavailable("could not verify token", err)
+	}
+	if revoked {
+		return nil, errRevokedToken
+	}
+
+	return claims, nil
+}
+
+// bearerToken takes the token out of an Authorization header. The Bearer scheme is optional, as
+// clients used to send the bare token.
+func bearerToken(header string) string {
+	header = strings.TrimSpace(header)
+	if scheme, token, found := strings.Cut(header, " "); found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return header
+}
+
+// setClaims stores the authenticated user in the request context
+func setClaims(c *gin.Context, claims *helpers.Claims) {
+	// Set user ID, email and role to context
+	c.Set("userID", claims.ID)
+	c.Set("userEmail", claims.Email)
+	c.Set("userRole", claims.Role)
+	c.Set("claims", claims)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// kindStatus is the HTTP status of each kind of domain error
+var kindStatus = map[apperrors.Kind]int{
+	apperrors.KindNotFound:     http.StatusNotFound,
+	apperrors.KindForbidden:    http.StatusForbidden,
+	apperrors.KindConflict:     http.StatusConflict,
+	apperrors.KindValidation:   http.StatusBadRequest,
+	apperrors.KindUnauthorized: http.StatusUnauthorized,
+	apperrors.KindUnavailable:  http.StatusServiceUnavailable,
+}
+
+// statusClientClosedRequest is the non-standard status nginx logs for requests the client gave up on
+const statusClientClosedRequest = 499
+
+// ErrorHandler renders the error a handler or middleware passed to ctx.Error as a BaseResponse, with the
+// status of its kind and its code. Causes are logged, never sent; errors that are not domain errors are
+// answered with 500. A request whose context ended fails with whatever its queries returned, so it is
+// answered by why it ended instead: 504 when it timed out, and 499 when the client went away.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, helpers.NewCodedErrorResponse(http.StatusTooManyRequests, "login_throttled", err.Error(), nil))
+			return
+		}
+
+		switch c.Request.Context().Err() {
+		case context.Canceled:
+			// Nobody reads the response, the status only shows in the access log
+			c.AbortWithStatus(statusClientClosedRequest)
+			return
+		case context.DeadlineExceeded:
+			log.Printf("error: %s %s: request timed out: %v", c.Request.Method, c.FullPath(), err)
+			c.JSON(http.StatusGatewayTimeout, helpers.NewCodedErrorResponse(http.StatusGatewayTimeout, "request_timeout", "the request took too long", nil))
+			return
+		}
+
+		var appErr *apperrors.Error
+		if !errors.As(err, &appErr) {
+			log.Printf("error: %s %s: %v", c.Request.Method, c.FullPath(), err)
+			c.JSON(http.StatusInternalServerError, helpers.NewCodedErrorResponse(http.StatusInternalServerError, "internal_error", "internal server error", nil))
+			return
+		}
+
+		status, ok := kindStatus[appErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		if appErr.Err != nil {
+			log.Printf("error: %s %s: %v", c.Request.Method, c.FullPath(), err)
+		}
+		c.JSON(status, helpers.NewCodedErrorResponse(status, appErr.Code, appErr.Message, appErr.Fields))
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission allows the request only if the authenticated user's role grants every
+// given permission. It must run after AuthMiddleware.
+func RequirePermission(permissions ...entities.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := entities.Role(c.GetString("userRole"))
+		for _, permission := range permissions {
+			if !role.HasPermission(permission) {
+				_ = c.Error(apperrors.Forbidden("permission_denied", "you do not have permission to perform this action"))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -2,21 +2,49 @@ package middleware
 
 import (
 	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 )
 
-// RateLimiter func
-func RateLimiter(r rate.Limit, b int) gin.HandlerFunc {
-	limiter := rate.NewLimiter(r, b)
+// RateLimit limits each client to the policy. Clients are the signed-in user when an auth middleware ran
+// before, and the client IP otherwise. The budget is reported in the RateLimit-* headers, and rejected
+// requests get Retry-After.
+func RateLimit(limiter services.RateLimiter, policy services.RateLimitPolicy) gin.HandlerFunc {
+	policyHeader := strconv.Itoa(policy.Limit) + ";w=" + strconv.Itoa(int(policy.Period.Seconds()))
+
 	return func(c *gin.Context) {
-		if !limiter.AllowN(time.Now(), 1) {
-			c.JSON(http.StatusTooManyRequests, helpers.NewErrorResponse(http.StatusTooManyRequests, "Rate limit exceeded"))
+		key := "ip-" + c.ClientIP()
+		if userID := c.GetUint("userID"); userID != 0 {
+			key = "user-" + strconv.FormatUint(uint64(userID), 10)
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), policy, key)
+		if err != nil {
+			// Never turn a limiter failure into an outage
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policyHeader)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+			c.JSON(http.StatusTooManyRequests, helpers.NewCodedErrorResponse(http.StatusTooManyRequests, "rate_limit_exceeded", "Rate limit exceeded", nil))
 			c.Abort()
 			return
 		}
 		c.Next()
 	}
 }
+
+// ceilSeconds rounds a duration up to whole seconds, as the headers expect
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
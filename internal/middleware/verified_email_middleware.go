@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail lets signed-in users perform the action only once they verified their email, when
+// the action is listed in VERIFIED_EMAIL_ACTIONS. It must run after AuthMiddleware or
+// OptionalAuthMiddleware; anonymous requests are left to them.
+func RequireVerifiedEmail(userService services.UserService, action string) gin.HandlerFunc {
+	required := false
+	for _, verifiedAction := range config.LoadConfig().VerifiedEmailActions {
+		required = required || verifiedAction == action
+	}
+
+	return func(c *gin.Context) {
+		userID := c.GetUint("userID")
+		if !required || userID == 0 {
+			c.Next()
+			return
+		}
+
+		user, err := userService.FindAuthorByID(c.Request.Context(), userID)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if !user.IsEmailVerified() {
+			_ = c.Error(apperrors.Forbidden("email_not_verified", "verify your email address to perform this action"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// XSS func
+// XSS func. Post content is Markdown that is sanitized when it is rendered, so it is passed through as written,
+// passwords are compared as typed and profile URLs are validated as http(s) links by the user service.
 func XSS() gin.HandlerFunc {
-	var xssMiddleware xss.XssMw
+	xssMiddleware := xss.XssMw{FieldsToSkip: []string{"content", "current_password", "new_password", "avatar_url", "website"}}
 	return xssMiddleware.RemoveXss()
 }
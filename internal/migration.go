@@ -8,9 +8,37 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file" // Needed for file-based migrations
 	"gorm.io/gorm"
 	"log"
+	"os"
 	"strconv"
+	"strings"
 )
 
+// migrationsDir holds the numbered migration files
+const migrationsDir = "db/migrations"
+
+// LatestMigrationVersion returns the number of the newest migration in db/migrations, the version the
+// schema must be at for this build
+func LatestMigrationVersion() (uint, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return 0, fmt.Errorf("could not read migrations: %v", err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		number, _, found := strings.Cut(entry.Name(), "_")
+		if !found || !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+		version, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		latest = max(latest, uint(version))
+	}
+	return latest, nil
+}
+
 // RunMigrations run migration script
 func RunMigrations(db *gorm.DB) error {
 	// Get *sql.DB from GORM
@@ -27,7 +55,7 @@ func RunMigrations(db *gorm.DB) error {
 
 	// Initialize the migrate instance
 	m, err := migrate.NewWithDatabaseInstance(
-		"file://db/migrations", // Path to the migrations directory
+		"file://"+migrationsDir, // Path to the migrations directory
 		"mysql", driver,
 	)
 	if err != nil {
@@ -59,7 +87,7 @@ func RollbackMigrations(db *gorm.DB, stepsStr string) error {
 
 	// Initialize the migrate instance
 	m, err := migrate.NewWithDatabaseInstance(
-		"file://db/migrations", // Path to the migrations directory
+		"file://"+migrationsDir, // Path to the migrations directory
 		"mysql", driver,
 	)
 	if err != nil {
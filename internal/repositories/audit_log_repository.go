@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository interface
+type AuditLogRepository interface {
+	Create(ctx context.Context, auditLog *entities.AuditLog) error
+	FindAllWithFilters(ctx context.Context, params *dto.ListQuery) ([]entities.AuditLog, error)
+	Count(ctx context.Context, params *dto.ListQuery) (int64, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository initializes audit log repository
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, auditLog *entities.AuditLog) error {
+	return r.db.WithContext(ctx).Create(auditLog).Error
+}
+
+func (r *auditLogRepository) FindAllWithFilters(ctx context.Context, params *dto.ListQuery) ([]entities.AuditLog, error) {
+	var auditLogs []entities.AuditLog
+	query := r.db.WithContext(ctx).Model(&entities.AuditLog{})
+
+	// Apply search filter
+	if params.Search != "" {
+		query = query.Where("(action LIKE ? OR resource_type LIKE ?)", "%"+params.Search+"%", "%"+params.Search+"%")
+	}
+	query = applyListFilters(query, params)
+
+	// Apply sorting and pagination, newest first by default
+	offset := (params.Page - 1) * params.PageSize
+	err := applyListSort(query, params, "created_at desc", "id desc").Offset(offset).Limit(params.PageSize).Find(&auditLogs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return auditLogs, nil
+}
+
+func (r *auditLogRepository) Count(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&entities.AuditLog{})
+
+	// Apply search filter
+	if params.Search != "" {
+		query = query.Where("(action LIKE ? OR resource_type LIKE ?)", "%"+params.Search+"%", "%"+params.Search+"%")
+	}
+	query = applyListFilters(query, params)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"gorm.io/gorm"
+)
+
+// CategoryRepository interface
+type CategoryRepository interface {
+	Create(ctx context.Context, category *entities.Category) error
+	FindBySlug(ctx context.Context, slug string) (*entities.Category, error)
+	FindBySlugs(ctx context.Context, slugs []string) ([]*entities.Category, error)
+	FindAllWithPostCounts(ctx context.Context, params *dto.ListQuery) ([]entities.TaxonomyPostCount, error)
+	CountWithPosts(ctx context.Context, params *dto.ListQuery) (int64, error)
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository initializes category repository
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *entities.Category) error {
+	return conflictOnDuplicate(r.db.WithContext(ctx).Create(category).Error, "category_exists", "category slug already exists")
+}
+
+func (r *categoryRepository) FindBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindBySlugs(ctx context.Context, slugs []string) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	if len(slugs) == 0 {
+		return categories, nil
+	}
+	err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Order("name asc").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) FindAllWithPostCounts(ctx context.Context, params *dto.ListQuery) ([]entities.TaxonomyPostCount, error) {
+	return findTaxonomyWithPostCounts(r.db.WithContext(ctx), "categories", "post_categories", "category_id", true, params)
+}
+
+func (r *categoryRepository) CountWithPosts(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	return countTaxonomyWithPosts(r.db.WithContext(ctx), "categories", "post_categories", "category_id", true, params)
+}
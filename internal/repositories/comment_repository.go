@@ -1,16 +1,28 @@
 package repositories
 
 import (
+	"context"
+	"errors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 	"github.com/dedenfarhanhub/blog-service/internal/entities"
 	"gorm.io/gorm"
+	"time"
 )
 
 // CommentRepository interface
 type CommentRepository interface {
-	Create(comment *entities.Comment) error
-	FindAllByPostIDWithFilters(postID uint, params *dto.QueryParams) ([]entities.Comment, error)
-	CountByPostID(postID uint, params *dto.QueryParams) (int64, error)
+	Create(ctx context.Context, comment *entities.Comment) error
+	FindByID(ctx context.Context, id uint) (*entities.Comment, error)
+	Delete(ctx context.Context, id uint) error
+	FindAllByPostIDWithFilters(ctx context.Context, postID uint, params *dto.ListQuery) ([]entities.Comment, error)
+	CountByPostID(ctx context.Context, postID uint, params *dto.ListQuery) (int64, error)
+	FindRootsByPostIDWithFilters(ctx context.Context, postID uint, params *dto.ListQuery) ([]entities.Comment, error)
+	CountRootsByPostID(ctx context.Context, postID uint, params *dto.ListQuery) (int64, error)
+	FindRepliesByRootIDs(ctx context.Context, rootIDs []uint, viewerID uint) ([]entities.Comment, error)
+	FindAllForModeration(ctx context.Context, params *dto.ListQuery) ([]entities.Comment, error)
+	CountForModeration(ctx context.Context, params *dto.ListQuery) (int64, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]entities.Comment, error)
+	UpdateStatus(ctx context.Context, ids []uint, status string, moderatorID uint) (int64, error)
 }
 
 type commentRepository struct {
@@ -22,28 +34,47 @@ func NewCommentRepository(db *gorm.DB) CommentRepository {
 	return &commentRepository{db: db}
 }
 
-func (r *commentRepository) Create(comment *entities.Comment) error {
-	return r.db.Create(comment).Error
+func (r *commentRepository) Create(ctx context.Context, comment *entities.Comment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
 }
 
-func (r *commentRepository) FindAllByPostIDWithFilters(postID uint, params *dto.QueryParams) ([]entities.Comment, error) {
+func (r *commentRepository) FindByID(ctx context.Context, id uint) (*entities.Comment, error) {
+	var comment entities.Comment
+	if err := r.db.WithContext(ctx).First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entities.Comment{}, id).Error
+}
+
+func (r *commentRepository) FindAllByPostIDWithFilters(ctx context.Context, postID uint, params *dto.ListQuery) ([]entities.Comment, error) {
 	var comments []entities.Comment
-	query := r.db.Model(&entities.Comment{}).Where("post_id = ?", postID)
+	query := applyCommentVisibility(r.db.WithContext(ctx).Model(&entities.Comment{}).Where("post_id = ?", postID), params.ViewerID)
 
 	// Apply search filter
 	if params.Search != "" {
-		query = query.Where("author_name LIKE ? OR content LIKE ?", "%"+params.Search+"%", "%"+params.Search+"%")
+		query = query.Where("(author_name LIKE ? OR content LIKE ?)", "%"+params.Search+"%", "%"+params.Search+"%")
 	}
+	query = applyListFilters(query, params)
 
-	// Apply sorting
-	if params.SortBy != "" {
-		if params.SortOrder == "desc" {
-			query = query.Order(params.SortBy + " desc")
-		} else {
-			query = query.Order(params.SortBy + " asc")
+	if params.KeysetSort {
+		query, err := applyKeysetPagination(query, params)
+		if err != nil {
+			return nil, err
 		}
+		err = query.Find(&comments).Error
+		return comments, err
 	}
 
+	// Apply sorting
+	query = applyListSort(query, params).Order("id asc")
+
 	// Apply pagination
 	offset := (params.Page - 1) * params.PageSize
 	err := query.Offset(offset).Limit(params.PageSize).Find(&comments).Error
@@ -54,14 +85,15 @@ func (r *commentRepository) FindAllByPostIDWithFilters(postID uint, params *dto.
 	return comments, nil
 }
 
-func (r *commentRepository) CountByPostID(postID uint, params *dto.QueryParams) (int64, error) {
+func (r *commentRepository) CountByPostID(ctx context.Context, postID uint, params *dto.ListQuery) (int64, error) {
 	var count int64
-	query := r.db.Model(&entities.Comment{}).Where("post_id = ?", postID)
+	query := applyCommentVisibility(r.db.WithContext(ctx).Model(&entities.Comment{}).Where("post_id = ?", postID), params.ViewerID)
 
 	// Apply search filter
 	if params.Search != "" {
-		query = query.Where("author_name LIKE ? OR content LIKE ?", "%"+params.Search+"%", "%"+params.Search+"%")
+		query = query.Where("(author_name LIKE ? OR content LIKE ?)", "%"+params.Search+"%", "%"+params.Search+"%")
 	}
+	query = applyListFilters(query, params)
 
 	// Count the total
 	if err := query.Count(&count).Error; err != nil {
@@ -70,3 +102,109 @@ func (r *commentRepository) CountByPostID(postID uint, params *dto.QueryParams)
 
 	return count, nil
 }
+
+func (r *commentRepository) FindRootsByPostIDWithFilters(ctx context.Context, postID uint, params *dto.ListQuery) ([]entities.Comment, error) {
+	var comments []entities.Comment
+	query := applyCommentVisibility(r.db.WithContext(ctx).Model(&entities.Comment{}).Where("post_id = ? AND parent_id IS NULL", postID), params.ViewerID)
+
+	// Apply search filter
+	if params.Search != "" {
+		query = query.Where("(author_name LIKE ? OR content LIKE ?)", "%"+params.Search+"%", "%"+params.Search+"%")
+	}
+	query = applyListFilters(query, params)
+
+	// Apply sorting, oldest threads first by default
+	query = applyListSort(query, params, "created_at asc").Order("id asc")
+
+	// Apply pagination
+	offset := (params.Page - 1) * params.PageSize
+	err := query.Offset(offset).Limit(params.PageSize).Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *commentRepository) CountRootsByPostID(ctx context.Context, postID uint, params *dto.ListQuery) (int64, error) {
+	var count int64
+	query := applyCommentVisibility(r.db.WithContext(ctx).Model(&entities.Comment{}).Where("post_id = ? AND parent_id IS NULL", postID), params.ViewerID)
+
+	// Apply search filter
+	if params.Search != "" {
+		query = query.Where("(author_name LIKE ? OR content LIKE ?)", "%"+params.Search+"%", "%"+params.Search+"%")
+	}
+	query = applyListFilters(query, params)
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *commentRepository) FindRepliesByRootIDs(ctx context.Context, rootIDs []uint, viewerID uint) ([]entities.Comment, error) {
+	var comments []entities.Comment
+	if len(rootIDs) == 0 {
+		return comments, nil
+	}
+	query := applyCommentVisibility(r.db.WithContext(ctx).Where("root_id IN ?", rootIDs), viewerID)
+	err := query.Order("created_at asc").Order("id asc").Find(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) FindAllForModeration(ctx context.Context, params *dto.ListQuery) ([]entities.Comment, error) {
+	var comments []entities.Comment
+	query := applyModerationFilters(r.db.WithContext(ctx).Model(&entities.Comment{}), params)
+
+	// Oldest first by default, so the queue is worked through in arrival order
+	offset := (params.Page - 1) * params.PageSize
+	err := applyListSort(query, params, "created_at asc").Order("id asc").Offset(offset).Limit(params.PageSize).Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *commentRepository) CountForModeration(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	var count int64
+	if err := applyModerationFilters(r.db.WithContext(ctx).Model(&entities.Comment{}), params).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *commentRepository) FindByIDs(ctx context.Context, ids []uint) ([]entities.Comment, error) {
+	var comments []entities.Comment
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) UpdateStatus(ctx context.Context, ids []uint, status string, moderatorID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entities.Comment{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":       status,
+		"moderated_by": moderatorID,
+		"moderated_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
+
+// applyCommentVisibility limits public listings to approved comments, plus the viewer's own pending ones
+func applyCommentVisibility(query *gorm.DB, viewerID uint) *gorm.DB {
+	if viewerID != 0 {
+		return query.Where("(status = ? OR (status = ? AND user_id = ?))", entities.CommentStatusApproved, entities.CommentStatusPending, viewerID)
+	}
+	return query.Where("status = ?", entities.CommentStatusApproved)
+}
+
+// applyModerationFilters applies the status, post and search filters of the moderation queue
+func applyModerationFilters(query *gorm.DB, params *dto.ListQuery) *gorm.DB {
+	query = applyListFilters(query, params)
+
+	if params.Search != "" {
+		query = query.Where("(author_name LIKE ? OR content LIKE ?)", "%"+params.Search+"%", "%"+params.Search+"%")
+	}
+
+	return query
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"gorm.io/gorm"
+)
+
+// conflictOnDuplicate reports a unique key violation as a conflict with the given code and message,
+// keeping the database error as its cause. Other errors are returned as they are.
+func conflictOnDuplicate(err error, code string, message string) error {
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		return err
+	}
+	conflict := apperrors.Conflict(code, message)
+	conflict.Err = err
+	return conflict
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// HealthRepository interface
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (uint, bool, error)
+}
+
+type healthRepository struct {
+	db *gorm.DB
+}
+
+// NewHealthRepository initializes health repository
+func NewHealthRepository(db *gorm.DB) HealthRepository {
+	return &healthRepository{db: db}
+}
+
+func (r *healthRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// MigrationVersion returns the schema version recorded by golang-migrate and whether its last migration
+// failed halfway (dirty). A database that was never migrated is at version 0.
+func (r *healthRepository) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	var state struct {
+		Version uint
+		Dirty   bool
+	}
+	err := r.db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&state).Error
+	return state.Version, state.Dirty, err
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"gorm.io/gorm"
+)
+
+// filterOperators maps filter operators to SQL
+var filterOperators = map[dto.FilterOperator]string{
+	dto.OpEq:  "=",
+	dto.OpNe:  "<>",
+	dto.OpGt:  ">",
+	dto.OpGte: ">=",
+	dto.OpLt:  "<",
+	dto.OpLte: "<=",
+}
+
+// applyListFilters applies the column filters of a list query. Columns come from the resource's
+// ListSpec, never from the request; filters without a column are left to the caller.
+func applyListFilters(query *gorm.DB, listQuery *dto.ListQuery) *gorm.DB {
+	for _, filter := range listQuery.Filters {
+		if filter.Column == "" {
+			continue
+		}
+		if filter.Operator == dto.OpIn {
+			query = query.Where(filter.Column+" IN ?", filter.Values)
+		} else {
+			query = query.Where(filter.Column+" "+filterOperators[filter.Operator]+" ?", filter.Values[0])
+		}
+	}
+	return query
+}
+
+// applyListSort orders by the requested fields, falling back to the given default ordering
+func applyListSort(query *gorm.DB, listQuery *dto.ListQuery, fallback ...string) *gorm.DB {
+	if len(listQuery.Sort) == 0 {
+		for _, order := range fallback {
+			query = query.Order(order)
+		}
+		return query
+	}
+
+	for _, field := range listQuery.Sort {
+		if field.Desc {
+			query = query.Order(field.Column + " desc")
+		} else {
+			query = query.Order(field.Column + " asc")
+		}
+	}
+	return query
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+// applyKeysetPagination orders by the sort column and then by id, and either continues from the cursor
+// or falls back to the page offset. One extra row is fetched so the caller can tell whether more follow.
+// The list must be sorted on a single keyset column, see helpers.IsKeysetSort.
+func applyKeysetPagination(query *gorm.DB, params *dto.ListQuery) (*gorm.DB, error) {
+	descending := params.Sort[0].Desc
+	cursor := params.Keyset
+	if cursor != nil && cursor.Backward {
+		// Walk the list in reverse; the service restores the order of the page
+		descending = !descending
+	}
+
+	operator, direction := ">", "asc"
+	if descending {
+		operator, direction = "<", "desc"
+	}
+
+	column := params.Sort[0].Column
+	switch {
+	case cursor == nil:
+		query = query.Offset((params.Page - 1) * params.PageSize)
+	case column == "id":
+		query = query.Where("id "+operator+" ?", cursor.ID)
+	default:
+		value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("("+column+" "+operator+" ? OR ("+column+" = ? AND id "+operator+" ?))", value, value, cursor.ID)
+	}
+
+	if column != "id" {
+		query = query.Order(column + " " + direction)
+	}
+	return query.Order("id " + direction).Limit(params.PageSize + 1), nil
+}
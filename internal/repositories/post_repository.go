@@ -1,21 +1,35 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 // PostRepository interface
 type PostRepository interface {
-	Create(post *entities.Post) error
-	FindByID(id uint) (*entities.Post, error)
-	FindAll() ([]entities.Post, error)
-	Update(post *entities.Post) error
-	Delete(id uint) error
-	FindAllWithFilters(params *dto.QueryParams) ([]entities.Post, error)
-	Count(params *dto.QueryParams) (int64, error)
+	Create(ctx context.Context, post *entities.Post, revision *entities.PostRevision) error
+	FindByID(ctx context.Context, id uint) (*entities.Post, error)
+	FindAll(ctx context.Context) ([]entities.Post, error)
+	Update(ctx context.Context, post *entities.Post, revision *entities.PostRevision) error
+	Delete(ctx context.Context, id uint) error
+	FindAllWithFilters(ctx context.Context, params *dto.ListQuery) ([]entities.Post, error)
+	Count(ctx context.Context, params *dto.ListQuery) (int64, error)
+	FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]entities.Post, error)
+	PublishScheduled(ctx context.Context, id uint) (bool, error)
+	FindIDBySlug(ctx context.Context, slug string) (uint, error)
+	SlugTaken(ctx context.Context, slug string, postID uint) (bool, error)
+	AddSlugAlias(ctx context.Context, postID uint, oldSlug string, newSlug string) error
+	FindSlugAliases(ctx context.Context, postID uint) ([]string, error)
+	FindSlugsByAuthor(ctx context.Context, authorID uint) (map[uint][]string, error)
+	Search(ctx context.Context, query string, titleWeight float64, params *dto.ListQuery) ([]entities.PostSearchHit, error)
+	CountSearch(ctx context.Context, query string) (int64, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]entities.Post, error)
 }
 
 type postRepository struct {
@@ -27,13 +41,24 @@ func NewPostRepository(db *gorm.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
-func (r *postRepository) Create(post *entities.Post) error {
-	return r.db.Create(post).Error
+// Create stores the post together with its first revision
+func (r *postRepository) Create(ctx context.Context, post *entities.Post, revision *entities.PostRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if post.Tags, err = lockPostTags(tx, post.Tags); err != nil {
+			return err
+		}
+		if err := tx.Create(post).Error; err != nil {
+			return conflictOnDuplicate(err, "slug_taken", "another post took this slug, try again")
+		}
+		revision.PostID = post.ID
+		return createRevision(tx, revision)
+	})
 }
 
-func (r *postRepository) FindByID(id uint) (*entities.Post, error) {
+func (r *postRepository) FindByID(ctx context.Context, id uint) (*entities.Post, error) {
 	var post entities.Post
-	if err := r.db.Preload("Author").First(&post, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Author").Preload("Tags").Preload("Categories").First(&post, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // Mengembalikan nil jika user tidak ditemukan
 		}
@@ -42,38 +67,66 @@ func (r *postRepository) FindByID(id uint) (*entities.Post, error) {
 	return &post, nil
 }
 
-func (r *postRepository) FindAll() ([]entities.Post, error) {
+func (r *postRepository) FindAll(ctx context.Context) ([]entities.Post, error) {
 	var posts []entities.Post
-	err := r.db.Find(&posts).Error
+	err := r.db.WithContext(ctx).Find(&posts).Error
 	return posts, err
 }
 
-func (r *postRepository) Update(post *entities.Post) error {
-	return r.db.Save(post).Error
+// Update saves the post and replaces its tags and categories with the ones set on the entity. The
+// revision, when given, is stored in the same transaction.
+func (r *postRepository) Update(ctx context.Context, post *entities.Post, revision *entities.PostRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if post.Tags, err = lockPostTags(tx, post.Tags); err != nil {
+			return err
+		}
+		if err := tx.Omit("Tags", "Categories").Save(post).Error; err != nil {
+			return conflictOnDuplicate(err, "slug_taken", "another post took this slug, try again")
+		}
+		tags := tx.Model(post).Association("Tags")
+		if err := replaceAssociation(tags, len(post.Tags), post.Tags); err != nil {
+			return err
+		}
+		categories := tx.Model(post).Association("Categories")
+		if err := replaceAssociation(categories, len(post.Categories), post.Categories); err != nil {
+			return err
+		}
+		if revision == nil {
+			return nil
+		}
+		return createRevision(tx, revision)
+	})
 }
 
-func (r *postRepository) Delete(id uint) error {
-	return r.db.Delete(&entities.Post{}, id).Error
+// replaceAssociation replaces the associated records, clearing them when there are none
+func replaceAssociation(association *gorm.Association, length int, values interface{}) error {
+	if length == 0 {
+		return association.Clear()
+	}
+	return association.Replace(values)
 }
 
-func (r *postRepository) FindAllWithFilters(params *dto.QueryParams) ([]entities.Post, error) {
-	var posts []entities.Post
-	query := r.db.Model(&entities.Post{}).Preload("Author")
+func (r *postRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entities.Post{}, id).Error
+}
 
-	// Apply search filter
-	if params.Search != "" {
-		query = query.Where("title LIKE ? OR content LIKE ?", "%"+params.Search+"%", "%"+params.Search+"%")
-	}
+func (r *postRepository) FindAllWithFilters(ctx context.Context, params *dto.ListQuery) ([]entities.Post, error) {
+	var posts []entities.Post
+	query := applyPostFilters(r.db.WithContext(ctx).Model(&entities.Post{}).Preload("Author").Preload("Tags").Preload("Categories"), params)
 
-	// Apply sorting
-	if params.SortBy != "" {
-		if params.SortOrder == "desc" {
-			query = query.Order(params.SortBy + " desc")
-		} else {
-			query = query.Order(params.SortBy + " asc")
+	if params.KeysetSort {
+		query, err := applyKeysetPagination(query, params)
+		if err != nil {
+			return nil, err
 		}
+		err = query.Find(&posts).Error
+		return posts, err
 	}
 
+	// Apply sorting, with the id as tie-breaker so pages do not overlap
+	query = applyListSort(query, params).Order("id asc")
+
 	// Apply pagination
 	offset := (params.Page - 1) * params.PageSize
 	err := query.Offset(offset).Limit(params.PageSize).Find(&posts).Error
@@ -84,14 +137,9 @@ func (r *postRepository) FindAllWithFilters(params *dto.QueryParams) ([]entities
 	return posts, nil
 }
 
-func (r *postRepository) Count(params *dto.QueryParams) (int64, error) {
+func (r *postRepository) Count(ctx context.Context, params *dto.ListQuery) (int64, error) {
 	var count int64
-	query := r.db.Model(&entities.Post{})
-
-	// Apply search filter
-	if params.Search != "" {
-		query = query.Where("title LIKE ? OR content LIKE ?", "%"+params.Search+"%", "%"+params.Search+"%")
-	}
+	query := applyPostFilters(r.db.WithContext(ctx).Model(&entities.Post{}), params)
 
 	// Count the total
 	if err := query.Count(&count).Error; err != nil {
@@ -100,3 +148,159 @@ func (r *postRepository) Count(params *dto.QueryParams) (int64, error) {
 
 	return count, nil
 }
+
+func (r *postRepository) FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]entities.Post, error) {
+	var posts []entities.Post
+	err := r.db.WithContext(ctx).Where("status = ? AND published_at <= ?", entities.PostStatusScheduled, now).
+		Order("published_at asc").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// PublishScheduled flips a scheduled post to published and reports whether this call did it,
+// so concurrent schedulers never publish the same post twice
+func (r *postRepository) PublishScheduled(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entities.Post{}).
+		Where("id = ? AND status = ?", id, entities.PostStatusScheduled).
+		Update("status", entities.PostStatusPublished)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FindIDBySlug resolves a current or previous slug to a post ID, returning 0 when no post uses it
+func (r *postRepository) FindIDBySlug(ctx context.Context, slug string) (uint, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).Model(&entities.Post{}).Where("slug = ?", slug).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		if err := r.db.WithContext(ctx).Model(&entities.PostSlugAlias{}).Where("slug = ?", slug).Limit(1).Pluck("post_id", &ids).Error; err != nil {
+			return 0, err
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+// SlugTaken reports whether another post uses the slug, either as its slug or as an alias
+func (r *postRepository) SlugTaken(ctx context.Context, slug string, postID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.Post{}).Where("slug = ? AND id <> ?", slug, postID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	err := r.db.WithContext(ctx).Model(&entities.PostSlugAlias{}).Where("slug = ? AND post_id <> ?", slug, postID).Count(&count).Error
+	return count > 0, err
+}
+
+// AddSlugAlias keeps the old slug of a post as an alias. When the post takes back one of its
+// previous slugs, that alias is dropped since the slug is current again.
+func (r *postRepository) AddSlugAlias(ctx context.Context, postID uint, oldSlug string, newSlug string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("slug = ? AND post_id = ?", newSlug, postID).Delete(&entities.PostSlugAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entities.PostSlugAlias{Slug: oldSlug, PostID: postID}).Error
+	})
+}
+
+func (r *postRepository) FindSlugAliases(ctx context.Context, postID uint) ([]string, error) {
+	var slugs []string
+	err := r.db.WithContext(ctx).Model(&entities.PostSlugAlias{}).Where("post_id = ?", postID).Pluck("slug", &slugs).Error
+	return slugs, err
+}
+
+// FindSlugsByAuthor maps the IDs of an author's posts to their current and previous slugs
+func (r *postRepository) FindSlugsByAuthor(ctx context.Context, authorID uint) (map[uint][]string, error) {
+	var posts []entities.Post
+	if err := r.db.WithContext(ctx).Select("id", "slug").Where("author_id = ?", authorID).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	slugs := make(map[uint][]string, len(posts))
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		slugs[post.ID] = []string{post.Slug}
+		ids = append(ids, post.ID)
+	}
+	if len(ids) == 0 {
+		return slugs, nil
+	}
+
+	var aliases []entities.PostSlugAlias
+	if err := r.db.WithContext(ctx).Where("post_id IN ?", ids).Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		slugs[alias.PostID] = append(slugs[alias.PostID], alias.Slug)
+	}
+	return slugs, nil
+}
+
+// Search ranks published posts matching a boolean-mode FULLTEXT query, weighting title matches
+func (r *postRepository) Search(ctx context.Context, query string, titleWeight float64, params *dto.ListQuery) ([]entities.PostSearchHit, error) {
+	var hits []entities.PostSearchHit
+	offset := (params.Page - 1) * params.PageSize
+	err := r.db.WithContext(ctx).Model(&entities.Post{}).
+		Select("id, MATCH(title) AGAINST (? IN BOOLEAN MODE) * ? + MATCH(content) AGAINST (? IN BOOLEAN MODE) AS score", query, titleWeight, query).
+		Where("status = ?", entities.PostStatusPublished).
+		Where("MATCH(title, content) AGAINST (? IN BOOLEAN MODE)", query).
+		Order("score desc, published_at desc").
+		Offset(offset).
+		Limit(params.PageSize).
+		Scan(&hits).Error
+	return hits, err
+}
+
+func (r *postRepository) CountSearch(ctx context.Context, query string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entities.Post{}).
+		Where("status = ?", entities.PostStatusPublished).
+		Where("MATCH(title, content) AGAINST (? IN BOOLEAN MODE)", query).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *postRepository) FindByIDs(ctx context.Context, ids []uint) ([]entities.Post, error) {
+	var posts []entities.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&posts).Error
+	return posts, err
+}
+
+// applyPostFilters applies the visibility rules, the search and the list filters shared by listing and counting
+func applyPostFilters(query *gorm.DB, params *dto.ListQuery) *gorm.DB {
+	// Only published posts are public, other states are visible to their author only
+	if params.ViewerID != 0 {
+		query = query.Where("(status = ? OR author_id = ?)", entities.PostStatusPublished, params.ViewerID)
+	} else {
+		query = query.Where("status = ?", entities.PostStatusPublished)
+	}
+
+	// Apply the column filters, then the taxonomy filters through the join tables
+	query = applyListFilters(query, params)
+	for _, filter := range params.Filters {
+		switch filter.Field {
+		case "tag":
+			query = query.Where("id IN (SELECT post_tags.post_id FROM post_tags JOIN tags ON tags.id = post_tags.tag_id WHERE tags.slug IN ?)", filter.Values)
+		case "category":
+			query = query.Where("id IN (SELECT post_categories.post_id FROM post_categories JOIN categories ON categories.id = post_categories.category_id WHERE categories.slug IN ?)", filter.Values)
+		}
+	}
+
+	// Apply search filter through the FULLTEXT index
+	if terms := helpers.ParseSearchQuery(params.Search); len(terms) > 0 {
+		query = query.Where("MATCH(title, content) AGAINST (? IN BOOLEAN MODE)", helpers.BooleanFullTextQuery(terms))
+	}
+
+	return query
+}
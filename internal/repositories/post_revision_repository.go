@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostRevisionRepository interface. Revisions are written by PostRepository, together with the post.
+type PostRevisionRepository interface {
+	FindAllByPostID(ctx context.Context, postID uint) ([]entities.PostRevision, error)
+	FindByPostIDAndRevision(ctx context.Context, postID uint, revision uint) (*entities.PostRevision, error)
+}
+
+type postRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostRevisionRepository initializes post revision repository
+func NewPostRevisionRepository(db *gorm.DB) PostRevisionRepository {
+	return &postRevisionRepository{db: db}
+}
+
+// createRevision assigns the next revision number of the post and stores the revision, within the
+// transaction tx
+func createRevision(tx *gorm.DB, revision *entities.PostRevision) error {
+	var latest uint
+	err := tx.Model(&entities.PostRevision{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("post_id = ?", revision.PostID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	revision.Revision = latest + 1
+	return tx.Create(revision).Error
+}
+
+func (r *postRevisionRepository) FindAllByPostID(ctx context.Context, postID uint) ([]entities.PostRevision, error) {
+	var revisions []entities.PostRevision
+	err := r.db.WithContext(ctx).Where("post_id = ?", postID).Order("revision desc").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *postRevisionRepository) FindByPostIDAndRevision(ctx context.Context, postID uint, revision uint) (*entities.PostRevision, error) {
+	var postRevision entities.PostRevision
+	if err := r.db.WithContext(ctx).Where("post_id = ? AND revision = ?", postID, revision).First(&postRevision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &postRevision, nil
+}
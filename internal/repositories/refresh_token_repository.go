@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"gorm.io/gorm"
+	"time"
+)
+
+// RefreshTokenRepository interface
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+	MarkRotated(ctx context.Context, id uint) (bool, error)
+	SetReplacedBy(ctx context.Context, id uint, replacedByID uint) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllByUserID(ctx context.Context, userID uint) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository initializes refresh token repository
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *entities.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	var token entities.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRotated revokes an active token and reports whether this call was the one that revoked it,
+// so two concurrent refreshes with the same token cannot both succeed
+func (r *refreshTokenRepository) MarkRotated(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepository) SetReplacedBy(ctx context.Context, id uint, replacedByID uint) error {
+	return r.db.WithContext(ctx).Model(&entities.RefreshToken{}).Where("id = ?", id).Update("replaced_by_id", replacedByID).Error
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepository) RevokeAllByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&entities.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
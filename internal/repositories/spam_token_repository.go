@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SpamTokenRepository interface
+type SpamTokenRepository interface {
+	FindByTokens(ctx context.Context, tokens []string) ([]entities.SpamToken, error)
+	Increment(ctx context.Context, tokens []string, isSpam bool) error
+}
+
+type spamTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewSpamTokenRepository initializes spam token repository
+func NewSpamTokenRepository(db *gorm.DB) SpamTokenRepository {
+	return &spamTokenRepository{db: db}
+}
+
+func (r *spamTokenRepository) FindByTokens(ctx context.Context, tokens []string) ([]entities.SpamToken, error) {
+	var spamTokens []entities.SpamToken
+	if len(tokens) == 0 {
+		return spamTokens, nil
+	}
+	err := r.db.WithContext(ctx).Where("token IN ?", tokens).Find(&spamTokens).Error
+	return spamTokens, err
+}
+
+// Increment adds one spam or ham occurrence to every token, inserting tokens seen for the first time
+func (r *spamTokenRepository) Increment(ctx context.Context, tokens []string, isSpam bool) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	column := "ham_count"
+	if isSpam {
+		column = "spam_count"
+	}
+
+	rows := make([]entities.SpamToken, 0, len(tokens))
+	for _, token := range tokens {
+		row := entities.SpamToken{Token: token}
+		if isSpam {
+			row.SpamCount = 1
+		} else {
+			row.HamCount = 1
+		}
+		rows = append(rows, row)
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{column: gorm.Expr(column + " + 1")}),
+	}).CreateInBatches(rows, 500).Error
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// TagRepository interface
+type TagRepository interface {
+	FindOrCreate(ctx context.Context, tags []entities.Tag) ([]*entities.Tag, error)
+	FindAllWithPostCounts(ctx context.Context, params *dto.ListQuery) ([]entities.TaxonomyPostCount, error)
+	CountWithPosts(ctx context.Context, params *dto.ListQuery) (int64, error)
+	DeleteUnused(ctx context.Context, createdBefore time.Time) (int64, error)
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository initializes tag repository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindOrCreate inserts the tags whose slug is not taken yet and returns every requested tag
+func (r *tagRepository) FindOrCreate(ctx context.Context, tags []entities.Tag) ([]*entities.Tag, error) {
+	var existing []*entities.Tag
+	if len(tags) == 0 {
+		return existing, nil
+	}
+
+	slugs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		slugs = append(slugs, tag.Slug)
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Order("name asc").Find(&existing).Error
+	return existing, err
+}
+
+func (r *tagRepository) FindAllWithPostCounts(ctx context.Context, params *dto.ListQuery) ([]entities.TaxonomyPostCount, error) {
+	return findTaxonomyWithPostCounts(r.db.WithContext(ctx), "tags", "post_tags", "tag_id", false, params)
+}
+
+func (r *tagRepository) CountWithPosts(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	return countTaxonomyWithPosts(r.db.WithContext(ctx), "tags", "post_tags", "tag_id", false, params)
+}
+
+// unusedTag matches the tags no post refers to
+const unusedTag = "NOT EXISTS (SELECT 1 FROM post_tags WHERE post_tags.tag_id = tags.id)"
+
+// DeleteUnused removes tags no post refers to anymore. Tags created after createdBefore are kept, so a
+// tag that was just created for a post being saved is usually not collected before it is attached. The
+// candidates are locked and checked again as they are deleted, so a tag a post attached in the meantime
+// stays; see lockPostTags for the other side.
+func (r *tagRepository) DeleteUnused(ctx context.Context, createdBefore time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Model(&entities.Tag{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("created_at < ?", createdBefore).
+			Where(unusedTag).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		result := tx.Where("id IN ?", ids).Where(unusedTag).Delete(&entities.Tag{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// lockPostTags makes sure the tags about to be attached to a post still exist and locks them until the
+// transaction tx ends. A tag the collector deleted since it was looked up is inserted again, and the
+// collector cannot delete the others before the post refers to them.
+func lockPostTags(tx *gorm.DB, tags []*entities.Tag) ([]*entities.Tag, error) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	missing := make([]entities.Tag, 0, len(tags))
+	slugs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		missing = append(missing, entities.Tag{Name: tag.Name, Slug: tag.Slug})
+		slugs = append(slugs, tag.Slug)
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error; err != nil {
+		return nil, err
+	}
+
+	var locked []*entities.Tag
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Where("slug IN ?", slugs).Order("name asc").Find(&locked).Error
+	return locked, err
+}
+
+// findTaxonomyWithPostCounts lists tags or categories with their number of published posts, most used first.
+// Unless includeUnused is set, only the ones used by at least one published post are listed.
+func findTaxonomyWithPostCounts(db *gorm.DB, table string, joinTable string, foreignKey string, includeUnused bool, params *dto.ListQuery) ([]entities.TaxonomyPostCount, error) {
+	join := "JOIN "
+	if includeUnused {
+		join = "LEFT JOIN "
+	}
+
+	var items []entities.TaxonomyPostCount
+	query := db.Table(table).
+		Select(table+".id, "+table+".name, "+table+".slug, COUNT(posts.id) AS post_count").
+		Joins(join+joinTable+" ON "+joinTable+"."+foreignKey+" = "+table+".id").
+		Joins(join+"posts ON posts.id = "+joinTable+".post_id AND posts.status = ?", entities.PostStatusPublished).
+		Group(table + ".id, " + table + ".name, " + table + ".slug")
+	query = applyListSort(query, params, "post_count desc", table+".name asc")
+
+	if params.Search != "" {
+		query = query.Where(table+".name LIKE ?", "%"+params.Search+"%")
+	}
+
+	// Apply pagination
+	offset := (params.Page - 1) * params.PageSize
+	err := query.Offset(offset).Limit(params.PageSize).Scan(&items).Error
+	return items, err
+}
+
+// countTaxonomyWithPosts counts the tags or categories listed by findTaxonomyWithPostCounts
+func countTaxonomyWithPosts(db *gorm.DB, table string, joinTable string, foreignKey string, includeUnused bool, params *dto.ListQuery) (int64, error) {
+	var count int64
+	query := db.Table(table)
+	if !includeUnused {
+		query = query.Where("EXISTS (SELECT 1 FROM "+joinTable+" JOIN posts ON posts.id = "+joinTable+".post_id"+
+			" WHERE "+joinTable+"."+foreignKey+" = "+table+".id AND posts.status = ?)", entities.PostStatusPublished)
+	}
+
+	if params.Search != "" {
+		query = query.Where(table+".name LIKE ?", "%"+params.Search+"%")
+	}
+
+	err := query.Count(&count).Error
+	return count, err
+}
@@ -1,16 +1,25 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"github.com/dedenfarhanhub/blog-service/internal/entities"
 	"gorm.io/gorm"
+	"time"
 )
 
 // UserRepository interface
 type UserRepository interface {
-	Create(user *entities.User) error
-	FindByID(id uint) (*entities.User, error)
-	FindByEmail(email string) (*entities.User, error)
+	Create(ctx context.Context, user *entities.User) error
+	FindByID(ctx context.Context, id uint) (*entities.User, error)
+	FindByEmail(ctx context.Context, email string) (*entities.User, error)
+	UpdateRole(ctx context.Context, id uint, role string) error
+	UpdatePassword(ctx context.Context, id uint, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, id uint, verifiedAt time.Time) error
+	UpdateProfile(ctx context.Context, user *entities.User) error
+	SetPendingEmail(ctx context.Context, id uint, email *string) error
+	ChangeEmail(ctx context.Context, id uint, email string, verifiedAt time.Time) error
+	Delete(ctx context.Context, id uint) error
 }
 
 type userRepository struct {
@@ -22,13 +31,13 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *entities.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *entities.User) error {
+	return conflictOnDuplicate(r.db.WithContext(ctx).Create(user).Error, "email_taken", "email already in use")
 }
 
-func (r *userRepository) FindByID(id uint) (*entities.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*entities.User, error) {
 	var user entities.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // Mengembalikan nil jika user tidak ditemukan
 		}
@@ -37,9 +46,9 @@ func (r *userRepository) FindByID(id uint) (*entities.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) FindByEmail(email string) (*entities.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
 	var user entities.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // Mengembalikan nil jika user tidak ditemukan
 		}
@@ -47,3 +56,38 @@ func (r *userRepository) FindByEmail(email string) (*entities.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *userRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	return r.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	return r.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", id).Update("password_hash", passwordHash).Error
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uint, verifiedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", id).Update("email_verified_at", verifiedAt).Error
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, user *entities.User) error {
+	return r.db.WithContext(ctx).Model(user).Select("Name", "Bio", "AvatarURL", "Website").Updates(user).Error
+}
+
+func (r *userRepository) SetPendingEmail(ctx context.Context, id uint, email *string) error {
+	return r.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", id).Update("pending_email", email).Error
+}
+
+// ChangeEmail switches the user to the confirmed address, which is verified as of the confirmation
+func (r *userRepository) ChangeEmail(ctx context.Context, id uint, email string, verifiedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":             email,
+		"email_verified_at": verifiedAt,
+		"pending_email":     nil,
+	}).Error
+	return conflictOnDuplicate(err, "email_taken", "email already in use")
+}
+
+// Delete removes the user; their posts and tokens go with them, their comments stay without a user
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entities.User{}, id).Error
+}
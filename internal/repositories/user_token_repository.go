@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserTokenRepository interface
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *entities.UserToken) error
+	FindByHash(ctx context.Context, tokenHash string, purpose string) (*entities.UserToken, error)
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+	InvalidateByUserID(ctx context.Context, userID uint, purpose string) error
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewUserTokenRepository initializes user token repository
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) Create(ctx context.Context, token *entities.UserToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *userTokenRepository) FindByHash(ctx context.Context, tokenHash string, purpose string) (*entities.UserToken, error) {
+	var token entities.UserToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ? AND purpose = ?", tokenHash, purpose).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes an unused token and reports whether this call was the one that consumed it,
+// so the same token cannot be redeemed twice concurrently
+func (r *userTokenRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entities.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateByUserID consumes the outstanding tokens of a user, so only the latest one mailed is valid
+func (r *userTokenRepository) InvalidateByUserID(ctx context.Context, userID uint, purpose string) error {
+	return r.db.WithContext(ctx).Model(&entities.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
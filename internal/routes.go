@@ -1,30 +1,79 @@
 package internal
 
 import (
+	"github.com/dedenfarhanhub/blog-service/config"
 	"github.com/dedenfarhanhub/blog-service/docs"
 	"github.com/dedenfarhanhub/blog-service/internal/controllers"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
 	"github.com/dedenfarhanhub/blog-service/internal/middleware"
 	"github.com/dedenfarhanhub/blog-service/internal/repositories"
 	"github.com/dedenfarhanhub/blog-service/internal/services"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	"golang.org/x/time/rate"
 	"gorm.io/gorm"
-	"time"
+	"log"
 )
 
 // InitRouter initializes the Gin router with routes and middleware.
-func InitRouter(db *gorm.DB, redisService *services.RedisService) *gin.Engine {
+func InitRouter(db *gorm.DB, redisService *services.RedisService, healthService services.HealthService) *gin.Engine {
+	cfg := config.LoadConfig()
+
 	r := gin.Default()
+	// Only take the client IP from X-Forwarded-For behind known proxies, as rate limits are keyed on it
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// Access token keys are read once; a missing or unreadable key stops the server
+	jwtKeys, err := helpers.LoadJWTKeys(helpers.JWTKeyConfig{
+		Secret:         cfg.JWTSecret,
+		PrivateKeyFile: cfg.JWTPrivateKeyFile,
+		KeyID:          cfg.JWTKeyID,
+		PublicKeyFiles: cfg.JWTPublicKeyFiles,
+		Issuer:         cfg.JWTIssuer,
+		Audience:       cfg.JWTAudience,
+		ClockSkew:      cfg.JWTClockSkew,
+	})
+	if err != nil {
+		log.Fatalf("Invalid JWT key configuration: %v", err)
+	}
+	// Pagination cursors are signed with a shared key, so that any instance accepts them. Without
+	// JWT_SECRET there is nothing to fall back to, and an empty key would let anyone forge cursors.
+	if cfg.CursorSecret == "" {
+		log.Fatalf("CURSOR_SECRET must be set when tokens are not signed with JWT_SECRET")
+	}
+	cursorSecret := []byte(cfg.CursorSecret)
+
+	var rateLimiter services.RateLimiter = services.NewInMemoryRateLimiter()
+	if cfg.RateLimitBackend != "memory" {
+		rateLimiter = services.NewRedisRateLimiter(redisService, rateLimiter)
+	}
 
 	r.Use(gin.Logger())
+	r.Use(middleware.Metrics())
+	// Bounds every request; MySQL and Redis calls are cancelled with it
+	r.Use(middleware.RequestTimeout(cfg.RequestTimeout))
+	// Renders the errors of every handler and middleware below
+	r.Use(middleware.ErrorHandler())
 	if gin.Mode() == gin.ReleaseMode {
 		r.Use(middleware.SecurityMiddleware())
 		r.Use(middleware.XSS())
 	}
 	r.Use(middleware.Cors())
-	r.Use(middleware.RateLimiter(rate.Every(1*time.Minute), 60)) // 60 requests per minute
+
+	// Health Routes, for the liveness and readiness probes. They are registered ahead of the rate limiter,
+	// so probes never compete with client traffic for the budget of their IP.
+	healthController := controllers.NewHealthController(healthService)
+	r.GET("/healthz", healthController.Liveness)
+	r.GET("/readyz", healthController.Readiness)
+
+	// Identify signed-in users before the default limit, so it counts them by user rather than by IP
+	authMiddleware := middleware.AuthMiddleware(redisService, jwtKeys)
+	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(redisService, jwtKeys)
+	r.Use(optionalAuthMiddleware)
+	r.Use(middleware.RateLimit(rateLimiter, ratePolicy("default", cfg.RateLimitDefault)))
 
 	// Swagger endpoint
 	docs.SwaggerInfo.BasePath = "/"
@@ -34,33 +83,128 @@ func InitRouter(db *gorm.DB, redisService *services.RedisService) *gin.Engine {
 	userRepo := repositories.NewUserRepository(db)
 	postRepo := repositories.NewPostRepository(db)
 	commentRepo := repositories.NewCommentRepository(db)
+	refreshTokenRepo := repositories.NewRefreshTokenRepository(db)
+	auditLogRepo := repositories.NewAuditLogRepository(db)
+	postRevisionRepo := repositories.NewPostRevisionRepository(db)
+	spamTokenRepo := repositories.NewSpamTokenRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	userTokenRepo := repositories.NewUserTokenRepository(db)
 
 	// Initialize services
-	userService := services.NewUserService(userRepo, redisService)
-	postService := services.NewPostService(postRepo, userService, redisService)
-	commentService := services.NewCommentService(commentRepo, postService)
+	var searchService services.SearchService
+	if cfg.SearchBackend == "memory" {
+		searchService = services.NewInMemorySearchService(postRepo)
+	} else {
+		searchService = services.NewMySQLSearchService(postRepo)
+	}
+	auditService := services.NewAuditService(auditLogRepo)
+	authService := services.NewAuthService(refreshTokenRepo, userRepo, redisService, jwtKeys, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	var mailer services.Mailer = services.NewLogMailer(cfg.MailSinkFile, cfg.MailFrom)
+	if cfg.Mailer == "smtp" {
+		mailer = services.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+	accountService := services.NewAccountService(userRepo, userTokenRepo, authService, mailer, redisService)
+	loginGuard := services.NewLoginGuard(redisService, auditService)
+	userService := services.NewUserService(userRepo, postRepo, searchService, authService, auditService, accountService, loginGuard, redisService)
+	postService := services.NewPostService(postRepo, tagRepo, categoryRepo, searchService, userService, auditService, redisService, cursorSecret)
+	authorService := services.NewAuthorService(userRepo, postRepo, postService)
+	postRevisionService := services.NewPostRevisionService(postRevisionRepo, postRepo, postService)
+	spamAnalyzer := services.NewDefaultSpamAnalyzer(spamTokenRepo, redisService)
+	commentService := services.NewCommentService(commentRepo, postService, auditService, spamAnalyzer, cursorSecret)
+	tagService := services.NewTagService(tagRepo, redisService)
+	categoryService := services.NewCategoryService(categoryRepo, auditService)
 
 	// Initialize controllers
+	authController := controllers.NewAuthController(authService)
 	userController := controllers.NewUserController(userService)
+	accountController := controllers.NewAccountController(accountService)
 	postController := controllers.NewPostController(postService)
+	postRevisionController := controllers.NewPostRevisionController(postRevisionService)
 	commentController := controllers.NewCommentController(commentService)
+	auditController := controllers.NewAuditController(auditService)
+	tagController := controllers.NewTagController(tagService)
+	categoryController := controllers.NewCategoryController(categoryService)
+	searchController := controllers.NewSearchController(searchService)
+	authorController := controllers.NewAuthorController(authorService, postService)
+	jwksController := controllers.NewJWKSController(jwtKeys)
+
+	r.POST("/register", middleware.RateLimit(rateLimiter, ratePolicy("register", cfg.RateLimitRegister)), userController.Register)
+	r.POST("/login", middleware.RateLimit(rateLimiter, ratePolicy("login", cfg.RateLimitLogin)), userController.Login)
+
+	// Auth Routes
+	r.GET("/.well-known/jwks.json", jwksController.GetJWKS)
+	r.POST("/token/refresh", authController.Refresh)
+	r.POST("/logout", authMiddleware, authController.Logout)
 
-	r.POST("/register", userController.Register)
-	r.POST("/login", userController.Login)
+	// Account Routes
+	r.POST("/password/forgot", middleware.RateLimit(rateLimiter, ratePolicy("password_forgot", cfg.RateLimitLogin)), accountController.ForgotPassword)
+	r.POST("/password/reset", middleware.RateLimit(rateLimiter, ratePolicy("password_reset", cfg.RateLimitLogin)), accountController.ResetPassword)
+	r.GET("/verify-email", accountController.VerifyEmail)
+	r.POST("/verify-email/resend", authMiddleware, middleware.RateLimit(rateLimiter, ratePolicy("verify_resend", cfg.RateLimitRegister)), accountController.ResendVerification)
+	r.GET("/email/confirm", accountController.ConfirmEmailChange)
+
+	// Profile Routes
+	profileGroup := r.Group("/users/me", authMiddleware)
+	{
+		profileGroup.GET("", userController.GetProfile)
+		profileGroup.PATCH("", middleware.RateLimit(rateLimiter, ratePolicy("profile_update", cfg.RateLimitLogin)), userController.UpdateProfile)
+		profileGroup.DELETE("", middleware.RateLimit(rateLimiter, ratePolicy("account_delete", cfg.RateLimitLogin)), userController.DeleteAccount)
+		profileGroup.POST("/password", middleware.RateLimit(rateLimiter, ratePolicy("password_change", cfg.RateLimitLogin)), userController.ChangePassword)
+	}
+
+	// Admin Routes
+	r.PUT("/users/:id/role", authMiddleware, middleware.RequirePermission(entities.PermissionUserManage), userController.UpdateRole)
+	r.DELETE("/users/:id/lockout", authMiddleware, middleware.RequirePermission(entities.PermissionUserManage), userController.UnlockLogin)
+	r.GET("/audit-logs", authMiddleware, middleware.RequirePermission(entities.PermissionAuditRead), auditController.GetAll)
 
 	// Post Routes
 	postGroup := r.Group("/posts")
 	{
-		postGroup.POST("/", middleware.AuthMiddleware(), postController.Create)
-		postGroup.GET("/:id", postController.GetByID)
-		postGroup.GET("/", postController.GetAll)
-		postGroup.PUT("/:id", middleware.AuthMiddleware(), postController.Update)
-		postGroup.DELETE("/:id", middleware.AuthMiddleware(), postController.Delete)
+		postGroup.POST("/", authMiddleware, middleware.RequirePermission(entities.PermissionPostCreate), middleware.RequireVerifiedEmail(userService, "post:create"), postController.Create)
+		postGroup.GET("/:id", optionalAuthMiddleware, postController.GetByID)
+		postGroup.GET("/by-slug/:slug", optionalAuthMiddleware, postController.GetBySlug)
+		postGroup.GET("/", optionalAuthMiddleware, postController.GetAll)
+		postGroup.PUT("/:id", authMiddleware, postController.Update)
+		postGroup.DELETE("/:id", authMiddleware, postController.Delete)
+		postGroup.POST("/:id/publish", authMiddleware, postController.Publish)
+		postGroup.POST("/:id/unpublish", authMiddleware, postController.Unpublish)
+
+		// Revision Routes nested under Post
+		postGroup.GET("/:id/revisions", authMiddleware, postRevisionController.GetAll)
+		postGroup.GET("/:id/revisions/diff", authMiddleware, postRevisionController.Diff)
+		postGroup.GET("/:id/revisions/:rev", authMiddleware, postRevisionController.GetByRevision)
+		postGroup.POST("/:id/revisions/:rev/restore", authMiddleware, postRevisionController.Restore)
 
 		// Comment Routes nested under Post
-		postGroup.POST("/:id/comments", commentController.Create)
-		postGroup.GET("/:id/comments", commentController.GetAllByPostID)
+		postGroup.POST("/:id/comments", optionalAuthMiddleware, middleware.RateLimit(rateLimiter, ratePolicy("comment", cfg.RateLimitComment)), middleware.RequireVerifiedEmail(userService, "comment:create"), commentController.Create)
+		postGroup.GET("/:id/comments", optionalAuthMiddleware, commentController.GetAllByPostID)
+		postGroup.DELETE("/:id/comments/:commentId", authMiddleware, middleware.RequirePermission(entities.PermissionCommentModerate), commentController.Delete)
+	}
+
+	// Author Routes
+	r.GET("/authors/:id", optionalAuthMiddleware, authorController.GetByID)
+	r.GET("/authors/:id/posts", optionalAuthMiddleware, authorController.GetPosts)
+
+	// Search Routes
+	r.GET("/search", searchController.Search)
+
+	// Taxonomy Routes
+	r.GET("/tags", tagController.GetAll)
+	r.GET("/categories", categoryController.GetAll)
+	r.POST("/categories", authMiddleware, middleware.RequirePermission(entities.PermissionCategoryManage), categoryController.Create)
+
+	// Moderation Routes
+	moderationGroup := r.Group("/moderation", authMiddleware, middleware.RequirePermission(entities.PermissionCommentModerate))
+	{
+		moderationGroup.GET("/comments", commentController.GetModerationQueue)
+		moderationGroup.PUT("/comments/status", commentController.Moderate)
 	}
 
 	return r
 }
+
+// ratePolicy names a configured rate limit
+func ratePolicy(name string, limit config.RateLimit) services.RateLimitPolicy {
+	return services.RateLimitPolicy{Name: name, Limit: limit.Limit, Period: limit.Period}
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"github.com/dedenfarhanhub/blog-service/internal/services"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+// StartPostScheduler periodically publishes scheduled posts until the context is cancelled
+func StartPostScheduler(ctx context.Context, db *gorm.DB, redisService *services.RedisService, interval time.Duration) {
+	schedulerService := services.NewPostSchedulerService(repositories.NewPostRepository(db), redisService)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := schedulerService.PublishDuePosts(ctx)
+			if err != nil {
+				log.Printf("post scheduler: %v", err)
+				continue
+			}
+			if published > 0 {
+				log.Printf("post scheduler: published %d scheduled post(s)", published)
+			}
+		}
+	}
+}
+
+// StartTagCollector periodically deletes tags that are no longer used by any post until the context is cancelled
+func StartTagCollector(ctx context.Context, db *gorm.DB, redisService *services.RedisService, interval time.Duration) {
+	tagService := services.NewTagService(repositories.NewTagRepository(db), redisService)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := tagService.CollectGarbage(ctx)
+			if err != nil {
+				log.Printf("tag collector: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("tag collector: deleted %d unused tag(s)", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+
+	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/metrics"
+)
+
+// NewHTTPServer wraps the router in an http.Server listening on HTTP_ADDR with the configured timeouts
+func NewHTTPServer(handler http.Handler, cfg *config.Config) *http.Server {
+	return &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+		TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+}
+
+// NewMetricsServer serves /metrics on METRICS_ADDR. It is kept off the API listener, so only the
+// scraper reaches it, and it speaks plain HTTP on the internal network.
+func NewMetricsServer(cfg *config.Config) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	return &http.Server{
+		Addr:              cfg.MetricsAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+}
+
+// ServeHTTP accepts connections until the server is shut down, over TLS when a certificate is configured.
+// It returns nil once Shutdown or Close was called.
+func ServeHTTP(server *http.Server, cfg *config.Config) error {
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	var err error
+	if cfg.TLSCertFile != "" {
+		err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
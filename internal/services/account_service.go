@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+)
+
+// AccountService handles the flows that prove ownership of an email address: verifying it, changing
+// to a new one and resetting a forgotten password through a link mailed to it
+type AccountService interface {
+	SendEmailVerification(ctx context.Context, user *entities.User) error
+	ResendEmailVerification(ctx context.Context, userID uint) error
+	VerifyEmail(ctx context.Context, token string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, password string) error
+	RequestEmailChange(ctx context.Context, user *entities.User, email string) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+}
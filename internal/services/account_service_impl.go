@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"golang.org/x/crypto/bcrypt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// errInvalidUserToken is returned for unknown, used or expired tokens alike
+var errInvalidUserToken = apperrors.Validation("invalid_or_expired_token", "invalid or expired token")
+
+// AccountServiceImpl struct
+type AccountServiceImpl struct {
+	userRepo      repositories.UserRepository
+	userTokenRepo repositories.UserTokenRepository
+	authService   AuthService
+	mailer        Mailer
+	redisService  *RedisService
+}
+
+// NewAccountService initializes account service
+func NewAccountService(userRepo repositories.UserRepository, userTokenRepo repositories.UserTokenRepository, authService AuthService, mailer Mailer, redisService *RedisService) AccountService {
+	return &AccountServiceImpl{
+		userRepo:      userRepo,
+		userTokenRepo: userTokenRepo,
+		authService:   authService,
+		mailer:        mailer,
+		redisService:  redisService,
+	}
+}
+
+// SendEmailVerification mails a link to verify the user's email address
+func (s *AccountServiceImpl) SendEmailVerification(ctx context.Context, user *entities.User) error {
+	if user.IsEmailVerified() {
+		return apperrors.Conflict("email_already_verified", "email is already verified")
+	}
+
+	cfg := config.LoadConfig()
+	token, err := s.issueToken(ctx, user.ID, entities.UserTokenPurposeEmailVerification, cfg.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimRight(cfg.AppBaseURL, "/") + "/verify-email?token=" + url.QueryEscape(token)
+	return s.sendMail(ctx, &MailMessage{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Please confirm your email address by opening this link:\n\n" + link + "\n\n" +
+			"The link expires in " + cfg.EmailVerificationTTL.String() + ". If you did not create an account, you can ignore this email.\n",
+	})
+}
+
+// ResendEmailVerification mails a new verification link to a user who has not verified their email yet
+func (s *AccountServiceImpl) ResendEmailVerification(ctx context.Context, userID uint) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.SendEmailVerification(ctx, user)
+}
+
+// VerifyEmail redeems an email verification token
+func (s *AccountServiceImpl) VerifyEmail(ctx context.Context, token string) error {
+	userToken, err := s.redeemToken(ctx, token, entities.UserTokenPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.findUser(ctx, userToken.UserID)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.MarkEmailVerified(ctx, user.ID, time.Now()); err != nil {
+		return apperrors.Unavailable("failed to verify email", err)
+	}
+
+	invalidateUserCache(ctx, s.redisService, user)
+	return nil
+}
+
+// ForgotPassword mails a password reset link. It succeeds for unknown emails too, and mails in the
+// background, so the response does not reveal whether an email is registered.
+func (s *AccountServiceImpl) ForgotPassword(ctx context.Context, email string) error {
+	if err := validateEmail(email); err != nil {
+		return err
+	}
+
+	background, cancel := detach(ctx)
+	go func() {
+		defer cancel()
+		if err := s.sendPasswordReset(background, email); err != nil {
+			log.Printf("password reset: %v", err)
+		}
+	}()
+	return nil
+}
+
+// sendPasswordReset issues a reset token for the account of the email, if there is one, and mails it
+func (s *AccountServiceImpl) sendPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil || user == nil {
+		return err
+	}
+
+	cfg := config.LoadConfig()
+	token, err := s.issueToken(ctx, user.ID, entities.UserTokenPurposePasswordReset, cfg.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimRight(cfg.AppBaseURL, "/") + "/password/reset?token=" + url.QueryEscape(token)
+	return s.sendMail(ctx, &MailMessage{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Someone asked to reset the password of your account. Use this link to choose a new one:\n\n" + link + "\n\n" +
+			"The link expires in " + cfg.PasswordResetTTL.String() + " and works once. If you did not ask for it, you can ignore this email.\n",
+	})
+}
+
+// ResetPassword redeems a password reset token. Every session of the user is revoked, as whoever
+// held the old password may still be signed in.
+func (s *AccountServiceImpl) ResetPassword(ctx context.Context, token string, password string) error {
+	userToken, err := s.redeemToken(ctx, token, entities.UserTokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.findUser(ctx, userToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("password hashing failed: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, string(passwordHash)); err != nil {
+		return apperrors.Unavailable("failed to update password", err)
+	}
+
+	// Receiving the reset link proves ownership of the address as well
+	if !user.IsEmailVerified() {
+		if err := s.userRepo.MarkEmailVerified(ctx, user.ID, time.Now()); err != nil {
+			return apperrors.Unavailable("failed to verify email", err)
+		}
+	}
+
+	invalidateUserCache(ctx, s.redisService, user)
+	if err := s.authService.RevokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RequestEmailChange keeps the new address as pending and mails a confirmation link to it. The user
+// keeps signing in with the current address until the new one is confirmed.
+func (s *AccountServiceImpl) RequestEmailChange(ctx context.Context, user *entities.User, email string) error {
+	if err := s.userRepo.SetPendingEmail(ctx, user.ID, &email); err != nil {
+		return apperrors.Unavailable("failed to store pending email", err)
+	}
+	invalidateUserCache(ctx, s.redisService, user)
+
+	cfg := config.LoadConfig()
+	token, err := s.issueToken(ctx, user.ID, entities.UserTokenPurposeEmailChange, cfg.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimRight(cfg.AppBaseURL, "/") + "/email/confirm?token=" + url.QueryEscape(token)
+	return s.sendMail(ctx, &MailMessage{
+		To:      email,
+		Subject: "Confirm your new email address",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Please confirm that you want to use this address for your account by opening this link:\n\n" + link + "\n\n" +
+			"The link expires in " + cfg.EmailVerificationTTL.String() + ". If you did not ask for it, you can ignore this email.\n",
+	})
+}
+
+// ConfirmEmailChange redeems an email change token, switching the user to the pending address. The
+// previous address is told about the change, so a hijacked account does not go unnoticed.
+func (s *AccountServiceImpl) ConfirmEmailChange(ctx context.Context, token string) error {
+	userToken, err := s.redeemToken(ctx, token, entities.UserTokenPurposeEmailChange)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.findUser(ctx, userToken.UserID)
+	if err != nil {
+		return err
+	}
+	if user.PendingEmail == nil {
+		return errInvalidUserToken
+	}
+	email := *user.PendingEmail
+
+	existingUser, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return apperrors.Unavailable("failed to check existing email", err)
+	}
+	if existingUser != nil && existingUser.ID != user.ID {
+		return ErrEmailTaken
+	}
+
+	if err := s.userRepo.ChangeEmail(ctx, user.ID, email, time.Now()); err != nil {
+		return dependencyError("failed to change email", err)
+	}
+	invalidateUserCache(ctx, s.redisService, user)
+	invalidateUserCache(ctx, s.redisService, &entities.User{ID: user.ID, Email: email})
+
+	previousEmail := user.Email
+	background, cancel := detach(ctx)
+	go func() {
+		defer cancel()
+		err := s.mailer.Send(background, &MailMessage{
+			To:      previousEmail,
+			Subject: "Your email address was changed",
+			Body: "Hi " + user.Name + ",\n\n" +
+				"The email address of your account was changed to " + email + ".\n\n" +
+				"If you did not make this change, please reset your password and contact us.\n",
+		})
+		if err != nil {
+			log.Printf("email change notice for user %d: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// issueToken creates a token for the user, invalidating the ones issued before for the same purpose,
+// and returns it in clear; only its hash is stored
+func (s *AccountServiceImpl) issueToken(ctx context.Context, userID uint, purpose string, ttl time.Duration) (string, error) {
+	token, err := helpers.GenerateRandomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	if err := s.userTokenRepo.InvalidateByUserID(ctx, userID, purpose); err != nil {
+		return "", apperrors.Unavailable("failed to invalidate previous tokens", err)
+	}
+	userToken := &entities.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: helpers.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.userTokenRepo.Create(ctx, userToken); err != nil {
+		return "", apperrors.Unavailable("failed to store token", err)
+	}
+	return token, nil
+}
+
+// findUser loads the user a token or a request is for, failing when there is none
+func (s *AccountServiceImpl) findUser(ctx context.Context, userID uint) (*entities.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to find user", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// sendMail sends a message, reporting a failing mail server as the service being unavailable
+func (s *AccountServiceImpl) sendMail(ctx context.Context, message *MailMessage) error {
+	if err := s.mailer.Send(ctx, message); err != nil {
+		return apperrors.Unavailable("failed to send email", err)
+	}
+	return nil
+}
+
+// redeemToken looks up a token and marks it used
+func (s *AccountServiceImpl) redeemToken(ctx context.Context, token string, purpose string) (*entities.UserToken, error) {
+	if token == "" {
+		return nil, errInvalidUserToken
+	}
+
+	userToken, err := s.userTokenRepo.FindByHash(ctx, helpers.HashToken(token), purpose)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to check token", err)
+	}
+	if userToken == nil || !userToken.IsUsable() {
+		return nil, errInvalidUserToken
+	}
+
+	redeemed, err := s.userTokenRepo.MarkUsed(ctx, userToken.ID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to redeem token", err)
+	}
+	if !redeemed {
+		return nil, errInvalidUserToken
+	}
+	return userToken, nil
+}
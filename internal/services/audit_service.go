@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+// Audit actions
+const (
+	AuditActionPostUpdate     = "post.update"
+	AuditActionPostDelete     = "post.delete"
+	AuditActionCommentDelete  = "comment.delete"
+	AuditActionCommentStatus  = "comment.moderate"
+	AuditActionUserRole       = "user.role_change"
+	AuditActionCategoryCreate = "category.create"
+	AuditActionUserLockout    = "user.lockout"
+	AuditActionUserUnlock     = "user.unlock"
+	AuditActionUserDelete     = "user.delete"
+)
+
+// systemActor records actions taken by the service itself rather than by a user
+var systemActor = &dto.Actor{ID: 0, Role: "system"}
+
+// AuditService interface
+type AuditService interface {
+	Record(ctx context.Context, actor *dto.Actor, action string, resourceType string, resourceID uint, details string)
+	GetAll(ctx context.Context, params *dto.ListQuery) ([]*dto.AuditLogResponse, error)
+	Count(ctx context.Context, params *dto.ListQuery) (int64, error)
+}
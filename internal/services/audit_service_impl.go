@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"log"
+)
+
+// AuditServiceImpl struct
+type AuditServiceImpl struct {
+	auditLogRepo repositories.AuditLogRepository
+}
+
+// NewAuditService initializes audit service
+func NewAuditService(auditLogRepo repositories.AuditLogRepository) AuditService {
+	return &AuditServiceImpl{auditLogRepo: auditLogRepo}
+}
+
+// Record stores an audit entry. The audited action has already happened at this point,
+// so a failure to write the entry is logged instead of failing the request, and the entry is written
+// even if the client has gone away.
+func (s *AuditServiceImpl) Record(ctx context.Context, actor *dto.Actor, action string, resourceType string, resourceID uint, details string) {
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
+	auditLog := &entities.AuditLog{
+		ActorID:      actor.ID,
+		ActorRole:    actor.Role,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Details:      details,
+	}
+	if err := s.auditLogRepo.Create(ctx, auditLog); err != nil {
+		log.Printf("failed to record audit log %s on %s %d by user %d: %v", action, resourceType, resourceID, actor.ID, err)
+	}
+}
+
+// GetAll returns audit logs, newest first
+func (s *AuditServiceImpl) GetAll(ctx context.Context, params *dto.ListQuery) ([]*dto.AuditLogResponse, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 10 // Default page size
+	}
+
+	auditLogs, err := s.auditLogRepo.FindAllWithFilters(ctx, params)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve audit logs", err)
+	}
+
+	var auditLogResponses []*dto.AuditLogResponse
+	for _, auditLog := range auditLogs {
+		auditLogResponses = append(auditLogResponses, auditLog.ToAuditLogResponse())
+	}
+
+	return auditLogResponses, nil
+}
+
+// Count counts audit logs
+func (s *AuditServiceImpl) Count(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	count, err := s.auditLogRepo.Count(ctx, params)
+	if err != nil {
+		return 0, apperrors.Unavailable("failed to count audit logs", err)
+	}
+	return count, nil
+}
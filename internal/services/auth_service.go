@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+)
+
+// AuthService interface
+type AuthService interface {
+	IssueTokens(ctx context.Context, user *entities.User) (*dto.TokenResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*dto.TokenResponse, error)
+	Logout(ctx context.Context, claims *helpers.Claims, logoutRequest *dto.LogoutRequest) error
+	RevokeAllSessions(ctx context.Context, userID uint) error
+	RevokeAccessTokens(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"strconv"
+	"time"
+)
+
+// RevokedTokenEntity is the Redis entity type holding revoked access token IDs (jti)
+const RevokedTokenEntity = "revoked_token"
+
+// userAccessTokensEntity is the Redis entity type holding the IDs (jti) of the access tokens issued to a
+// user, until they expire
+const userAccessTokensEntity = "user_access_tokens"
+
+var (
+	errInvalidRefreshToken = apperrors.Unauthorized("invalid_refresh_token", "invalid refresh token")
+	errRefreshTokenReused  = apperrors.Unauthorized("refresh_token_reused", "refresh token reuse detected, please login again")
+)
+
+// AuthServiceImpl struct
+type AuthServiceImpl struct {
+	refreshTokenRepo repositories.RefreshTokenRepository
+	userRepo         repositories.UserRepository
+	redisService     *RedisService
+	tokenIssuer      helpers.TokenIssuer
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
+}
+
+// NewAuthService initializes auth service
+func NewAuthService(refreshTokenRepo repositories.RefreshTokenRepository, userRepo repositories.UserRepository, redisService *RedisService, tokenIssuer helpers.TokenIssuer, accessTokenTTL time.Duration, refreshTokenTTL time.Duration) AuthService {
+	return &AuthServiceImpl{
+		refreshTokenRepo: refreshTokenRepo,
+		userRepo:         userRepo,
+		redisService:     redisService,
+		tokenIssuer:      tokenIssuer,
+		accessTokenTTL:   accessTokenTTL,
+		refreshTokenTTL:  refreshTokenTTL,
+	}
+}
+
+// IssueTokens starts a new session for the user with a fresh refresh token family
+func (s *AuthServiceImpl) IssueTokens(ctx context.Context, user *entities.User) (*dto.TokenResponse, error) {
+	familyID, err := helpers.GenerateRandomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate token: %w", err)
+	}
+	tokens, _, err := s.issueTokens(ctx, user, familyID)
+	return tokens, err
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already rotated is treated as
+// theft and revokes every token in its family.
+func (s *AuthServiceImpl) Refresh(ctx context.Context, refreshToken string) (*dto.TokenResponse, error) {
+	existingToken, err := s.refreshTokenRepo.FindByHash(ctx, helpers.HashToken(refreshToken))
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to check refresh token", err)
+	}
+	if existingToken == nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	if existingToken.RevokedAt != nil {
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, existingToken.FamilyID); err != nil {
+			return nil, apperrors.Unavailable("failed to revoke refresh token family", err)
+		}
+		return nil, errRefreshTokenReused
+	}
+	if existingToken.IsExpired() {
+		return nil, apperrors.Unauthorized("refresh_token_expired", "refresh token expired")
+	}
+
+	rotated, err := s.refreshTokenRepo.MarkRotated(ctx, existingToken.ID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to rotate refresh token", err)
+	}
+	if !rotated {
+		// Another request rotated this token first, so this one is a replay
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, existingToken.FamilyID); err != nil {
+			return nil, apperrors.Unavailable("failed to revoke refresh token family", err)
+		}
+		return nil, errRefreshTokenReused
+	}
+
+	user, err := s.userRepo.FindByID(ctx, existingToken.UserID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to load user", err)
+	}
+	if user == nil {
+		return nil, errInvalidRefreshToken
+	}
+
+	tokens, newToken, err := s.issueTokens(ctx, user, existingToken.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.SetReplacedBy(ctx, existingToken.ID, newToken.ID); err != nil {
+		return nil, apperrors.Unavailable("failed to rotate refresh token", err)
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the current access token and the refresh token session(s) of the user
+func (s *AuthServiceImpl) Logout(ctx context.Context, claims *helpers.Claims, logoutRequest *dto.LogoutRequest) error {
+	if err := s.revokeAccessToken(ctx, claims); err != nil {
+		return err
+	}
+
+	if logoutRequest.AllSessions {
+		return s.RevokeAllSessions(ctx, claims.ID)
+	}
+
+	if logoutRequest.RefreshToken == "" {
+		return nil
+	}
+
+	existingToken, err := s.refreshTokenRepo.FindByHash(ctx, helpers.HashToken(logoutRequest.RefreshToken))
+	if err != nil {
+		return apperrors.Unavailable("failed to check refresh token", err)
+	}
+	if existingToken == nil || existingToken.UserID != claims.ID {
+		return apperrors.Validation("invalid_refresh_token", "invalid refresh token",
+			dto.FieldError{Field: "refresh_token", Message: "is not a refresh token of this user"})
+	}
+
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, existingToken.FamilyID); err != nil {
+		return apperrors.Unavailable("failed to revoke refresh token", err)
+	}
+	return nil
+}
+
+// RevokeAllSessions revokes every refresh token of the user
+func (s *AuthServiceImpl) RevokeAllSessions(ctx context.Context, userID uint) error {
+	if err := s.refreshTokenRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return apperrors.Unavailable("failed to revoke sessions", err)
+	}
+	return nil
+}
+
+// issueTokens signs a new access token and stores a new refresh token in the given family
+func (s *AuthServiceImpl) issueTokens(ctx context.Context, user *entities.User, familyID string) (*dto.TokenResponse, *entities.RefreshToken, error) {
+	accessToken, claims, err := s.tokenIssuer.GenerateToken(user.Email, user.ID, string(user.GetRole()), s.accessTokenTTL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not generate token: %w", err)
+	}
+	// Remember the token so it can be revoked when the user's role changes
+	userID := strconv.FormatUint(uint64(user.ID), 10)
+	if err := s.redisService.AddExpiringMember(ctx, userAccessTokensEntity, userID, claims.RegisteredClaims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, nil, apperrors.Unavailable("failed to track access token", err)
+	}
+
+	refreshToken, err := helpers.GenerateRandomToken(32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not generate token: %w", err)
+	}
+
+	refreshTokenEntity := &entities.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: helpers.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, refreshTokenEntity); err != nil {
+		return nil, nil, apperrors.Unavailable("failed to store refresh token", err)
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.accessTokenTTL.Seconds()),
+	}, refreshTokenEntity, nil
+}
+
+// RevokeAccessTokens revokes every access token issued to the user that has not expired yet. Their
+// refresh tokens keep working, so clients get new access tokens carrying the user's current role.
+func (s *AuthServiceImpl) RevokeAccessTokens(ctx context.Context, userID uint) error {
+	tokens, err := s.redisService.ExpiringMembers(ctx, userAccessTokensEntity, strconv.FormatUint(uint64(userID), 10))
+	if err != nil {
+		return apperrors.Unavailable("failed to find access tokens", err)
+	}
+
+	for tokenID, expiresAt := range tokens {
+		ttl := time.Until(expiresAt)
+		if ttl <= 0 {
+			continue
+		}
+		if err := s.redisService.SetEntity(ctx, RevokedTokenEntity, tokenID, true, ttl); err != nil {
+			return apperrors.Unavailable("failed to revoke access token", err)
+		}
+	}
+	return nil
+}
+
+// revokeAccessToken adds the token ID to the Redis revocation list until the token would expire anyway
+func (s *AuthServiceImpl) revokeAccessToken(ctx context.Context, claims *helpers.Claims) error {
+	if claims.RegisteredClaims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := s.redisService.SetEntity(ctx, RevokedTokenEntity, claims.RegisteredClaims.ID, true, ttl); err != nil {
+		return apperrors.Unavailable("failed to revoke access token", err)
+	}
+	return nil
+}
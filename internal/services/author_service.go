@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+// ErrAuthorNotFound is returned for author pages of unknown users
+var ErrAuthorNotFound = apperrors.NotFound("author_not_found", "author not found")
+
+// AuthorService serves the public pages of authors
+type AuthorService interface {
+	GetProfile(ctx context.Context, authorID uint, viewer *dto.Actor) (*dto.AuthorProfileResponse, error)
+	GetPosts(ctx context.Context, authorID uint, params *dto.ListQuery) ([]*dto.PostResponse, *dto.PageCursors, error)
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+)
+
+// AuthorServiceImpl struct
+type AuthorServiceImpl struct {
+	userRepo    repositories.UserRepository
+	postRepo    repositories.PostRepository
+	postService PostService
+}
+
+// NewAuthorService initializes author service
+func NewAuthorService(userRepo repositories.UserRepository, postRepo repositories.PostRepository, postService PostService) AuthorService {
+	return &AuthorServiceImpl{
+		userRepo:    userRepo,
+		postRepo:    postRepo,
+		postService: postService,
+	}
+}
+
+// GetProfile returns the public page of an author with the number of posts they published. The email
+// is only shown to the author themselves and to users who manage users.
+func (s *AuthorServiceImpl) GetProfile(ctx context.Context, authorID uint, viewer *dto.Actor) (*dto.AuthorProfileResponse, error) {
+	author, err := s.findAuthor(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count as an anonymous viewer, so the author's own drafts are not included
+	postCount, err := s.postRepo.Count(ctx, scopeToAuthor(&dto.ListQuery{}, author.ID))
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to count posts", err)
+	}
+
+	withEmail := viewer.ID == author.ID || entities.Role(viewer.Role).HasPermission(entities.PermissionUserManage)
+	return author.ToAuthorProfileResponse(postCount, withEmail), nil
+}
+
+// GetPosts lists the posts of an author through the filters and pagination of the post list. The list
+// query is scoped to the author in place, so counting it afterwards counts the author's posts.
+func (s *AuthorServiceImpl) GetPosts(ctx context.Context, authorID uint, params *dto.ListQuery) ([]*dto.PostResponse, *dto.PageCursors, error) {
+	if _, err := s.findAuthor(ctx, authorID); err != nil {
+		return nil, nil, err
+	}
+	return s.postService.GetAll(ctx, scopeToAuthor(params, authorID))
+}
+
+// findAuthor loads an author, failing with ErrAuthorNotFound when there is none
+func (s *AuthorServiceImpl) findAuthor(ctx context.Context, authorID uint) (*entities.User, error) {
+	author, err := s.userRepo.FindByID(ctx, authorID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to find author", err)
+	}
+	if author == nil {
+		return nil, ErrAuthorNotFound
+	}
+	return author, nil
+}
+
+// scopeToAuthor limits a post list to the posts of an author
+func scopeToAuthor(params *dto.ListQuery, authorID uint) *dto.ListQuery {
+	params.Filters = append(params.Filters, dto.Filter{
+		Field:    "author_id",
+		Column:   "author_id",
+		Operator: dto.OpEq,
+		Values:   []interface{}{authorID},
+	})
+	return params
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+// CategoryService interface
+type CategoryService interface {
+	Create(ctx context.Context, categoryRequest *dto.CategoryRequest, actor *dto.Actor) (*dto.CategoryResponse, error)
+	GetAll(ctx context.Context, params *dto.ListQuery) ([]*dto.CategoryResponse, error)
+	Count(ctx context.Context, params *dto.ListQuery) (int64, error)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+)
+
+// CategoryServiceImpl struct
+type CategoryServiceImpl struct {
+	categoryRepo repositories.CategoryRepository
+	auditService AuditService
+}
+
+// NewCategoryService initializes category service
+func NewCategoryService(categoryRepo repositories.CategoryRepository, auditService AuditService) CategoryService {
+	return &CategoryServiceImpl{
+		categoryRepo: categoryRepo,
+		auditService: auditService,
+	}
+}
+
+// Create adds a category with a unique slug
+func (s *CategoryServiceImpl) Create(ctx context.Context, categoryRequest *dto.CategoryRequest, actor *dto.Actor) (*dto.CategoryResponse, error) {
+	if !entities.Role(actor.Role).HasPermission(entities.PermissionCategoryManage) {
+		return nil, apperrors.Forbidden("permission_denied", "you do not have permission to manage categories")
+	}
+
+	slug := categoryRequest.Slug
+	if slug == "" {
+		slug = categoryRequest.Name
+	}
+	slug = helpers.Slugify(slug)
+	if slug == "" {
+		return nil, apperrors.Validation("invalid_category", "category slug must contain letters or digits",
+			dto.FieldError{Field: "slug", Message: "must contain letters or digits"})
+	}
+
+	existing, err := s.categoryRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to check category slug", err)
+	}
+	if existing != nil {
+		return nil, apperrors.Conflict("category_exists", "category slug already exists")
+	}
+
+	category := &entities.Category{
+		Name: categoryRequest.Name,
+		Slug: slug,
+	}
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, dependencyError("failed to create category", err)
+	}
+
+	s.auditService.Record(ctx, actor, AuditActionCategoryCreate, "category", category.ID, "slug: "+category.Slug)
+	return category.ToCategoryResponse(), nil
+}
+
+// GetAll lists every category with its number of published posts, most used first
+func (s *CategoryServiceImpl) GetAll(ctx context.Context, params *dto.ListQuery) ([]*dto.CategoryResponse, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 10 // Default page size
+	}
+
+	categories, err := s.categoryRepo.FindAllWithPostCounts(ctx, params)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve categories", err)
+	}
+
+	categoryResponses := make([]*dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, category.ToCategoryResponse())
+	}
+
+	return categoryResponses, nil
+}
+
+// Count counts the categories
+func (s *CategoryServiceImpl) Count(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	count, err := s.categoryRepo.CountWithPosts(ctx, params)
+	if err != nil {
+		return 0, apperrors.Unavailable("failed to count categories", err)
+	}
+	return count, nil
+}
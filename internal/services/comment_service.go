@@ -1,12 +1,26 @@
 package services
 
 import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 )
 
+// Errors of CommentService
+var (
+	ErrCommentNotFound       = apperrors.NotFound("comment_not_found", "comment not found")
+	ErrCannotModerateComment = apperrors.Forbidden("permission_denied", "you do not have permission to moderate comments")
+)
+
 // CommentService interface
 type CommentService interface {
-	Create(postID uint, commentRequest *dto.CommentRequest) (*dto.CommentResponse, error)
-	GetAllByPostID(postID uint, params *dto.QueryParams) ([]*dto.CommentResponse, error)
-	CountAllByPostID(postID uint, params *dto.QueryParams) (int64, error)
+	Create(ctx context.Context, postID uint, commentRequest *dto.CommentRequest) (*dto.CommentResponse, error)
+	GetAllByPostID(ctx context.Context, postID uint, params *dto.ListQuery) ([]*dto.CommentResponse, *dto.PageCursors, error)
+	CountAllByPostID(ctx context.Context, postID uint, params *dto.ListQuery) (int64, error)
+	GetTreeByPostID(ctx context.Context, postID uint, params *dto.ListQuery) ([]*dto.CommentTreeResponse, error)
+	CountRootsByPostID(ctx context.Context, postID uint, params *dto.ListQuery) (int64, error)
+	Delete(ctx context.Context, postID uint, commentID uint, actor *dto.Actor) error
+	GetModerationQueue(ctx context.Context, params *dto.ListQuery) ([]*dto.CommentResponse, error)
+	CountModerationQueue(ctx context.Context, params *dto.ListQuery) (int64, error)
+	Moderate(ctx context.Context, moderateRequest *dto.ModerateCommentsRequest, actor *dto.Actor) (*dto.ModerateCommentsResponse, error)
 }
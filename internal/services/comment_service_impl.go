@@ -1,44 +1,144 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/metrics"
 	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // CommentServiceImpl struct
 type CommentServiceImpl struct {
-	commentRepo repositories.CommentRepository
-	postService PostService
+	commentRepo  repositories.CommentRepository
+	postService  PostService
+	auditService AuditService
+	spamAnalyzer SpamAnalyzer
+	cursorSecret []byte
 }
 
 // Create func create comment
-func (s *CommentServiceImpl) Create(postID uint, commentRequest *dto.CommentRequest) (*dto.CommentResponse, error) {
+func (s *CommentServiceImpl) Create(ctx context.Context, postID uint, commentRequest *dto.CommentRequest) (*dto.CommentResponse, error) {
 	// Validate if the PostID exists
-	post, err := s.postService.GetPostByID(postID)
+	post, err := s.postService.GetPostByID(ctx, postID, 0)
 	if err != nil {
 		return nil, err
 	}
 	if post == nil {
-		return nil, errors.New("postID does not exist")
+		return nil, ErrPostNotFound
 	}
 
 	comment := &entities.Comment{
 		PostID:     postID,
 		AuthorName: commentRequest.AuthorName,
 		Content:    commentRequest.Content,
+		Status:     initialCommentStatus(post.CommentPolicy, commentRequest.UserID),
+	}
+	if commentRequest.UserID != 0 {
+		comment.UserID = &commentRequest.UserID
 	}
 
-	if err := s.commentRepo.Create(comment); err != nil {
-		return nil, err
+	if commentRequest.ParentID != nil {
+		if err := s.attachToParent(ctx, comment, *commentRequest.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.applySpamVerdict(ctx, comment, commentRequest)
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, apperrors.Unavailable("failed to create comment", err)
 	}
+	metrics.CommentsCreated.WithLabelValues(comment.Status).Inc()
 
 	return comment.ToCommentResponse(), nil
 }
 
+// applySpamVerdict scores the comment and holds it back when it looks like spam
+func (s *CommentServiceImpl) applySpamVerdict(ctx context.Context, comment *entities.Comment, commentRequest *dto.CommentRequest) {
+	verdict := s.spamAnalyzer.Analyze(ctx, &SpamCandidate{
+		PostID:     comment.PostID,
+		UserID:     commentRequest.UserID,
+		AuthorName: comment.AuthorName,
+		Content:    comment.Content,
+		Honeypot:   commentRequest.Website,
+		ClientIP:   commentRequest.ClientIP,
+	})
+	comment.SpamScore = verdict.Score
+	comment.SpamReasons = strings.Join(verdict.Reasons, entities.SpamReasonSeparator)
+
+	cfg := config.LoadConfig()
+	switch {
+	case verdict.Score >= cfg.SpamThreshold:
+		comment.Status = entities.CommentStatusSpam
+	case verdict.Score >= cfg.SpamReviewThreshold && comment.Status == entities.CommentStatusApproved:
+		comment.Status = entities.CommentStatusPending
+	}
+}
+
 // GetAllByPostID get all comments by post id
-func (s *CommentServiceImpl) GetAllByPostID(postID uint, params *dto.QueryParams) ([]*dto.CommentResponse, error) {
+func (s *CommentServiceImpl) GetAllByPostID(ctx context.Context, postID uint, params *dto.ListQuery) ([]*dto.CommentResponse, *dto.PageCursors, error) {
+	if err := s.checkPostVisible(ctx, postID, params.ViewerID); err != nil {
+		return nil, nil, err
+	}
+
+	// Oldest first unless asked otherwise, so a conversation reads top to bottom
+	if err := preparePagination(params, dto.CommentListSpec, s.cursorSecret); err != nil {
+		return nil, nil, err
+	}
+
+	comments, err := s.commentRepo.FindAllByPostIDWithFilters(ctx, postID, params)
+	if err != nil {
+		return nil, nil, apperrors.Unavailable("failed to retrieve comments", err)
+	}
+
+	comments, cursors := keysetPage(comments, params, s.cursorSecret, func(comment entities.Comment) (time.Time, uint) {
+		return comment.CreatedAt, comment.ID
+	})
+
+	var commentResponses []*dto.CommentResponse
+	for _, comment := range comments {
+		commentResponses = append(commentResponses, comment.ToCommentResponse())
+	}
+
+	return commentResponses, cursors, nil
+}
+
+// checkPostVisible fails with ErrPostNotFound unless the viewer can read the post, so the comments of
+// drafts and archived posts are not listed
+func (s *CommentServiceImpl) checkPostVisible(ctx context.Context, postID uint, viewerID uint) error {
+	post, err := s.postService.GetPostByID(ctx, postID, viewerID)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return ErrPostNotFound
+	}
+	return nil
+}
+
+// CountAllByPostID count all comments by post id
+func (s *CommentServiceImpl) CountAllByPostID(ctx context.Context, postID uint, params *dto.ListQuery) (int64, error) {
+	count, err := s.commentRepo.CountByPostID(ctx, postID, params)
+	if err != nil {
+		return 0, apperrors.Unavailable("failed to count comments", err)
+	}
+	return count, nil
+}
+
+// GetTreeByPostID pages through the top-level comments of a post and returns each one with its nested replies
+func (s *CommentServiceImpl) GetTreeByPostID(ctx context.Context, postID uint, params *dto.ListQuery) ([]*dto.CommentTreeResponse, error) {
+	if err := s.checkPostVisible(ctx, postID, params.ViewerID); err != nil {
+		return nil, err
+	}
+
 	if params.Page < 1 {
 		params.Page = 1
 	}
@@ -46,28 +146,316 @@ func (s *CommentServiceImpl) GetAllByPostID(postID uint, params *dto.QueryParams
 		params.PageSize = 10 // Default page size
 	}
 
-	comments, err := s.commentRepo.FindAllByPostIDWithFilters(postID, params)
+	roots, err := s.commentRepo.FindRootsByPostIDWithFilters(ctx, postID, params)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve comments", err)
+	}
+
+	rootIDs := make([]uint, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+
+	replies, err := s.commentRepo.FindRepliesByRootIDs(ctx, rootIDs, params.ViewerID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve replies", err)
+	}
+
+	hidden, err := s.findHiddenAncestors(ctx, roots, replies)
 	if err != nil {
 		return nil, err
 	}
 
-	var commentResponses []*dto.CommentResponse
+	return buildCommentTree(roots, replies, hidden), nil
+}
+
+// findHiddenAncestors loads the replies the viewer cannot see that still have visible replies below them,
+// so their place in the thread can be kept with a placeholder
+func (s *CommentServiceImpl) findHiddenAncestors(ctx context.Context, roots []entities.Comment, replies []entities.Comment) ([]entities.Comment, error) {
+	known := make(map[uint]bool, len(roots)+len(replies))
+	for _, comment := range roots {
+		known[comment.ID] = true
+	}
+	for _, comment := range replies {
+		known[comment.ID] = true
+	}
+
+	var hidden []entities.Comment
+	pending := replies
+	for len(pending) > 0 {
+		var missing []uint
+		for _, comment := range pending {
+			if comment.ParentID != nil && !known[*comment.ParentID] {
+				known[*comment.ParentID] = true
+				missing = append(missing, *comment.ParentID)
+			}
+		}
+		if len(missing) == 0 {
+			break
+		}
+
+		parents, err := s.commentRepo.FindByIDs(ctx, missing)
+		if err != nil {
+			return nil, apperrors.Unavailable("failed to retrieve replies", err)
+		}
+		hidden = append(hidden, parents...)
+		pending = parents
+	}
+	return hidden, nil
+}
+
+// CountRootsByPostID count the top-level comments of a post
+func (s *CommentServiceImpl) CountRootsByPostID(ctx context.Context, postID uint, params *dto.ListQuery) (int64, error) {
+	count, err := s.commentRepo.CountRootsByPostID(ctx, postID, params)
+	if err != nil {
+		return 0, apperrors.Unavailable("failed to count comments", err)
+	}
+	return count, nil
+}
+
+// attachToParent validates the parent comment and places the reply in its thread
+func (s *CommentServiceImpl) attachToParent(ctx context.Context, comment *entities.Comment, parentID uint) error {
+	parent, err := s.commentRepo.FindByID(ctx, parentID)
+	if err != nil {
+		return apperrors.Unavailable("failed to find parent comment", err)
+	}
+	if parent == nil || parent.PostID != comment.PostID {
+		return apperrors.Validation("invalid_parent", "parent comment does not belong to this post",
+			dto.FieldError{Field: "parent_id", Message: "must be a comment of this post"})
+	}
+	if parent.Status != entities.CommentStatusApproved {
+		return apperrors.Conflict("parent_not_approved", "cannot reply to a comment that is not approved")
+	}
+
+	maxDepth := config.LoadConfig().CommentMaxDepth
+	if parent.Depth+1 > maxDepth {
+		return apperrors.Validation("thread_too_deep", fmt.Sprintf("replies cannot be nested more than %d levels deep", maxDepth))
+	}
+
+	rootID := parent.ThreadRootID()
+	comment.ParentID = &parent.ID
+	comment.RootID = &rootID
+	comment.Depth = parent.Depth + 1
+	return nil
+}
+
+// Delete removes a comment as a moderation action
+func (s *CommentServiceImpl) Delete(ctx context.Context, postID uint, commentID uint, actor *dto.Actor) error {
+	if !entities.Role(actor.Role).HasPermission(entities.PermissionCommentModerate) {
+		return ErrCannotModerateComment
+	}
+
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return apperrors.Unavailable("failed to find comment", err)
+	}
+	if comment == nil || comment.PostID != postID {
+		return ErrCommentNotFound
+	}
+
+	if err := s.commentRepo.Delete(ctx, comment.ID); err != nil {
+		return apperrors.Unavailable("failed to delete comment", err)
+	}
+
+	s.auditService.Record(ctx, actor, AuditActionCommentDelete, "comment", comment.ID, "post_id: "+strconv.FormatUint(uint64(postID), 10))
+	return nil
+}
+
+// GetModerationQueue lists comments in a moderation status, pending by default, oldest first
+func (s *CommentServiceImpl) GetModerationQueue(ctx context.Context, params *dto.ListQuery) ([]*dto.CommentResponse, error) {
+	if err := normalizeModerationParams(params); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.FindAllForModeration(ctx, params)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve comments", err)
+	}
+
+	commentResponses := make([]*dto.CommentResponse, 0, len(comments))
 	for _, comment := range comments {
-		commentResponses = append(commentResponses, comment.ToCommentResponse())
+		commentResponses = append(commentResponses, comment.ToModerationResponse())
 	}
 
 	return commentResponses, nil
 }
 
-// CountAllByPostID count all comments by post id
-func (s *CommentServiceImpl) CountAllByPostID(postID uint, params *dto.QueryParams) (int64, error) {
-	return s.commentRepo.CountByPostID(postID, params)
+// CountModerationQueue counts comments in a moderation status
+func (s *CommentServiceImpl) CountModerationQueue(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	if err := normalizeModerationParams(params); err != nil {
+		return 0, err
+	}
+	count, err := s.commentRepo.CountForModeration(ctx, params)
+	if err != nil {
+		return 0, apperrors.Unavailable("failed to count comments", err)
+	}
+	return count, nil
+}
+
+// Moderate sets the moderation status of several comments and records each decision
+func (s *CommentServiceImpl) Moderate(ctx context.Context, moderateRequest *dto.ModerateCommentsRequest, actor *dto.Actor) (*dto.ModerateCommentsResponse, error) {
+	if !entities.Role(actor.Role).HasPermission(entities.PermissionCommentModerate) {
+		return nil, ErrCannotModerateComment
+	}
+	if !entities.IsValidCommentStatus(moderateRequest.Status) {
+		return nil, apperrors.Validation("invalid_status", "invalid comment status",
+			dto.FieldError{Field: "status", Message: "must be one of: approved, rejected, spam, pending"})
+	}
+
+	comments, err := s.commentRepo.FindByIDs(ctx, moderateRequest.CommentIDs)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to find comments", err)
+	}
+	if len(comments) == 0 {
+		return &dto.ModerateCommentsResponse{Updated: 0}, nil
+	}
+
+	ids := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+
+	updated, err := s.commentRepo.UpdateStatus(ctx, ids, moderateRequest.Status, actor.ID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to moderate comments", err)
+	}
+
+	// The decision is saved: audit it and learn from it even if the client goes away
+	ctx, cancel := detach(ctx)
+	defer cancel()
+	for _, comment := range comments {
+		s.auditService.Record(ctx, actor, AuditActionCommentStatus, "comment", comment.ID, comment.Status+" -> "+moderateRequest.Status)
+		s.learnFromDecision(ctx, &comment, moderateRequest.Status)
+	}
+
+	return &dto.ModerateCommentsResponse{Updated: updated}, nil
 }
 
-// NewCommentService initializes comment service
-func NewCommentService(commentRepo repositories.CommentRepository, postService PostService) CommentService {
+// learnFromDecision trains the spam analyzer when a moderator marks a comment as spam or approves it
+func (s *CommentServiceImpl) learnFromDecision(ctx context.Context, comment *entities.Comment, status string) {
+	if comment.Status == status {
+		return
+	}
+	candidate := &SpamCandidate{PostID: comment.PostID, AuthorName: comment.AuthorName, Content: comment.Content}
+	if comment.UserID != nil {
+		candidate.UserID = *comment.UserID
+	}
+	switch status {
+	case entities.CommentStatusSpam:
+		s.spamAnalyzer.Learn(ctx, candidate, true)
+	case entities.CommentStatusApproved:
+		s.spamAnalyzer.Learn(ctx, candidate, false)
+	}
+}
+
+// NewCommentService initializes comment service. cursorSecret signs the pagination cursors.
+func NewCommentService(commentRepo repositories.CommentRepository, postService PostService, auditService AuditService, spamAnalyzer SpamAnalyzer, cursorSecret []byte) CommentService {
 	return &CommentServiceImpl{
-		commentRepo: commentRepo,
-		postService: postService,
+		commentRepo:  commentRepo,
+		postService:  postService,
+		auditService: auditService,
+		spamAnalyzer: spamAnalyzer,
+		cursorSecret: cursorSecret,
+	}
+}
+
+// buildCommentTree nests the replies under their top-level comments, preserving the order of both. Hidden
+// replies are placeholders keeping the visible replies below them in place; they are left out of the counts.
+func buildCommentTree(roots []entities.Comment, replies []entities.Comment, hidden []entities.Comment) []*dto.CommentTreeResponse {
+	nodes := make(map[uint]*dto.CommentTreeResponse, len(roots)+len(replies)+len(hidden))
+	tree := make([]*dto.CommentTreeResponse, 0, len(roots))
+
+	for i := range roots {
+		node := &dto.CommentTreeResponse{CommentResponse: roots[i].ToCommentResponse(), Replies: []*dto.CommentTreeResponse{}}
+		nodes[roots[i].ID] = node
+		tree = append(tree, node)
+	}
+	for i := range replies {
+		nodes[replies[i].ID] = &dto.CommentTreeResponse{CommentResponse: replies[i].ToCommentResponse(), Replies: []*dto.CommentTreeResponse{}}
+	}
+	for i := range hidden {
+		nodes[hidden[i].ID] = &dto.CommentTreeResponse{CommentResponse: hidden[i].ToHiddenCommentResponse(), Hidden: true, Replies: []*dto.CommentTreeResponse{}}
+	}
+
+	// Siblings are attached in order of creation, so they keep their chronological order
+	all := make([]entities.Comment, 0, len(replies)+len(hidden))
+	all = append(append(all, replies...), hidden...)
+	sort.SliceStable(all, func(i, j int) bool {
+		if !all[i].CreatedAt.Equal(all[j].CreatedAt) {
+			return all[i].CreatedAt.Before(all[j].CreatedAt)
+		}
+		return all[i].ID < all[j].ID
+	})
+	for i := range all {
+		if all[i].ParentID == nil {
+			continue
+		}
+		parent, ok := nodes[*all[i].ParentID]
+		if !ok {
+			continue
+		}
+		node := nodes[all[i].ID]
+		parent.Replies = append(parent.Replies, node)
+		if !node.Hidden {
+			parent.ReplyCount++
+		}
+	}
+
+	for _, node := range tree {
+		countDescendants(node)
+	}
+
+	return tree
+}
+
+// countDescendants fills in the number of replies below every node of a thread
+func countDescendants(node *dto.CommentTreeResponse) int {
+	total := 0
+	for _, reply := range node.Replies {
+		total += countDescendants(reply)
+		if !reply.Hidden {
+			total++
+		}
+	}
+	node.DescendantCount = total
+	return total
+}
+
+// initialCommentStatus resolves the post's comment policy (or the global one) into the status of a new comment
+func initialCommentStatus(postPolicy string, userID uint) string {
+	policy := postPolicy
+	if policy == "" || policy == entities.CommentPolicyInherit {
+		policy = config.LoadConfig().CommentModerationPolicy
+	}
+
+	switch policy {
+	case entities.CommentPolicyOpen:
+		return entities.CommentStatusApproved
+	case entities.CommentPolicyAnonymous:
+		if userID != 0 {
+			return entities.CommentStatusApproved
+		}
+		return entities.CommentStatusPending
+	default:
+		return entities.CommentStatusPending
+	}
+}
+
+// normalizeModerationParams applies the moderation queue defaults: pending comments unless another status is asked for
+func normalizeModerationParams(params *dto.ListQuery) error {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 10 // Default page size
+	}
+	if params.Filter("status") == nil {
+		params.Filters = append(params.Filters, dto.Filter{
+			Field:    "status",
+			Column:   "status",
+			Operator: dto.OpEq,
+			Values:   []interface{}{entities.CommentStatusPending},
+		})
 	}
+	return nil
 }
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+)
+
+// threadComment is an approved comment of post 1, created id minutes after the epoch
+func threadComment(id uint, parentID uint, rootID uint, depth int) entities.Comment {
+	comment := entities.Comment{
+		ID:         id,
+		PostID:     1,
+		Depth:      depth,
+		AuthorName: "alice",
+		Content:    "comment",
+		Status:     entities.CommentStatusApproved,
+		CreatedAt:  time.Unix(0, 0).Add(time.Duration(id) * time.Minute),
+	}
+	if parentID != 0 {
+		comment.ParentID = &parentID
+		comment.RootID = &rootID
+	}
+	return comment
+}
+
+// treeShape renders the IDs of a tree, with hidden nodes prefixed by h
+func treeShape(nodes []*dto.CommentTreeResponse) []interface{} {
+	shape := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		id := interface{}(node.ID)
+		if node.Hidden {
+			id = fmt.Sprintf("h%d", node.ID)
+		}
+		shape = append(shape, id)
+		if len(node.Replies) > 0 {
+			shape = append(shape, treeShape(node.Replies))
+		}
+	}
+	return shape
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	// 1
+	// ├── 2 (rejected)
+	// │   ├── 4
+	// │   └── 6 (rejected)
+	// │       └── 7
+	// ├── 3
+	// └── 5
+	root := threadComment(1, 0, 0, 0)
+	rejected := threadComment(2, 1, 1, 1)
+	rejected.Status = entities.CommentStatusRejected
+	spam := threadComment(6, 2, 1, 2)
+	spam.Status = entities.CommentStatusSpam
+	replies := []entities.Comment{threadComment(3, 1, 1, 1), threadComment(4, 2, 1, 2), threadComment(5, 1, 1, 1), threadComment(7, 6, 1, 3)}
+
+	tree := buildCommentTree([]entities.Comment{root}, replies, []entities.Comment{spam, rejected})
+
+	want := []interface{}{uint(1), []interface{}{"h2", []interface{}{uint(4), "h6", []interface{}{uint(7)}}, uint(3), uint(5)}}
+	if got := treeShape(tree); !reflect.DeepEqual(got, want) {
+		t.Fatalf("tree = %v, want %v", got, want)
+	}
+
+	if tree[0].ReplyCount != 2 || tree[0].DescendantCount != 4 {
+		t.Errorf("root counts = %d replies, %d descendants, want 2 and 4", tree[0].ReplyCount, tree[0].DescendantCount)
+	}
+	placeholder := tree[0].Replies[0]
+	if placeholder.ReplyCount != 1 || placeholder.DescendantCount != 2 {
+		t.Errorf("placeholder counts = %d replies, %d descendants, want 1 and 2", placeholder.ReplyCount, placeholder.DescendantCount)
+	}
+	if placeholder.Content != "" || placeholder.AuthorName != "" || placeholder.Status != "" {
+		t.Errorf("placeholder exposes the hidden reply: %+v", placeholder.CommentResponse)
+	}
+	if placeholder.ParentID == nil || *placeholder.ParentID != 1 || placeholder.Depth != 1 {
+		t.Errorf("placeholder lost its place in the thread: %+v", placeholder.CommentResponse)
+	}
+}
+
+func TestBuildCommentTreeWithoutHiddenReplies(t *testing.T) {
+	roots := []entities.Comment{threadComment(2, 0, 0, 0), threadComment(1, 0, 0, 0)}
+	replies := []entities.Comment{threadComment(3, 1, 1, 1), threadComment(4, 3, 1, 2), threadComment(5, 2, 2, 1)}
+
+	tree := buildCommentTree(roots, replies, nil)
+
+	// Roots keep the order they were paged in
+	want := []interface{}{uint(2), []interface{}{uint(5)}, uint(1), []interface{}{uint(3), []interface{}{uint(4)}}}
+	if got := treeShape(tree); !reflect.DeepEqual(got, want) {
+		t.Fatalf("tree = %v, want %v", got, want)
+	}
+	if tree[1].ReplyCount != 1 || tree[1].DescendantCount != 2 {
+		t.Errorf("counts = %d replies, %d descendants, want 1 and 2", tree[1].ReplyCount, tree[1].DescendantCount)
+	}
+}
@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// detachedTimeout bounds the work a request leaves behind, such as mailing or finishing a saved change
+const detachedTimeout = time.Minute
+
+// detach returns a context for work that must not stop when the client goes away: it keeps the values
+// of the request context but neither its cancellation nor its deadline, and is bounded by detachedTimeout
+func detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), detachedTimeout)
+}
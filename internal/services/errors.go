@@ -0,0 +1,17 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+)
+
+// dependencyError reports a failure of the database or Redis as the service being unavailable, wrapping
+// the cause. Domain errors raised below, such as the conflicts of repositories, are passed on as they are.
+func dependencyError(message string, err error) error {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return apperrors.Unavailable(message, err)
+}
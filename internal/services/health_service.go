@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+// Readiness states
+const (
+	ReadinessReady        = "ready"
+	ReadinessUnready      = "unready"
+	ReadinessShuttingDown = "shutting_down"
+)
+
+// Dependency check states
+const (
+	DependencyUp   = "up"
+	DependencyDown = "down"
+)
+
+// HealthService interface
+type HealthService interface {
+	// Readiness checks every dependency; the server is ready only when all of them are up
+	Readiness(ctx context.Context) *dto.ReadinessResponse
+	// MarkShuttingDown makes the server unready for good, so load balancers stop sending it traffic
+	MarkShuttingDown()
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// readinessError is a failed check whose message is safe to show; other failures are only logged
+type readinessError string
+
+func (e readinessError) Error() string {
+	return string(e)
+}
+
+// HealthServiceImpl struct
+type HealthServiceImpl struct {
+	healthRepo       repositories.HealthRepository
+	redisService     *RedisService
+	migrationVersion uint
+	timeout          time.Duration
+	shuttingDown     atomic.Bool
+}
+
+// NewHealthService initializes health service. The schema must be at least at migrationVersion, and
+// each check gets timeout to answer.
+func NewHealthService(healthRepo repositories.HealthRepository, redisService *RedisService, migrationVersion uint, timeout time.Duration) HealthService {
+	return &HealthServiceImpl{
+		healthRepo:       healthRepo,
+		redisService:     redisService,
+		migrationVersion: migrationVersion,
+		timeout:          timeout,
+	}
+}
+
+// Readiness runs the checks concurrently, each with its own timeout. Once shutting down the server is
+// unready without checking anything.
+func (s *HealthServiceImpl) Readiness(ctx context.Context) *dto.ReadinessResponse {
+	response := &dto.ReadinessResponse{Status: ReadinessReady, Checks: map[string]dto.DependencyCheck{}}
+	if s.shuttingDown.Load() {
+		response.Status = ReadinessShuttingDown
+		return response
+	}
+
+	checks := map[string]func(ctx context.Context) error{
+		"mysql":      s.healthRepo.Ping,
+		"redis":      s.redisService.Ping,
+		"migrations": s.checkMigrations,
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+			result := s.runCheck(ctx, name, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Checks[name] = result
+			if result.Status != DependencyUp {
+				response.Status = ReadinessUnready
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return response
+}
+
+// MarkShuttingDown func
+func (s *HealthServiceImpl) MarkShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+// runCheck times a check against the timeout. Connection errors are logged rather than shown, as they
+// may name internal hosts.
+func (s *HealthServiceImpl) runCheck(ctx context.Context, name string, check func(ctx context.Context) error) dto.DependencyCheck {
+	checkCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(checkCtx)
+	result := dto.DependencyCheck{Status: DependencyUp, DurationMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err == nil {
+		return result
+	}
+
+	result.Status = DependencyDown
+	var stateErr readinessError
+	switch {
+	case errors.As(err, &stateErr):
+		result.Error = stateErr.Error()
+	case errors.Is(checkCtx.Err(), context.DeadlineExceeded):
+		result.Error = "timed out after " + s.timeout.String()
+	default:
+		result.Error = "check failed"
+	}
+	log.Printf("readiness: %s: %v", name, err)
+	return result
+}
+
+// checkMigrations requires the schema to have reached the latest migration this build ships. A newer
+// schema is accepted, so replicas of the previous release stay ready while a rollout migrates the database.
+func (s *HealthServiceImpl) checkMigrations(ctx context.Context) error {
+	version, dirty, err := s.healthRepo.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return readinessError(fmt.Sprintf("migration %d failed halfway and is marked dirty", version))
+	}
+	if version < s.migrationVersion {
+		return readinessError(fmt.Sprintf("schema is at version %d, expected %d", version, s.migrationVersion))
+	}
+	return nil
+}
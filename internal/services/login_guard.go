@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"strconv"
+	"time"
+)
+
+// LoginThrottledError is returned while an account or IP may not try to log in, with the time to wait
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return "too many failed login attempts, try again in " + strconv.Itoa(int(e.RetryAfter.Round(time.Second).Seconds())) + "s"
+}
+
+// LoginGuard counts failed logins per account and per IP, slows down repeated failures and locks accounts.
+// Accounts are identified by email, whether or not it is registered, so the guard behaves the same for
+// unknown emails.
+type LoginGuard interface {
+	// Check returns a *LoginThrottledError while the account or the IP is locked or backing off
+	Check(ctx context.Context, email string, clientIP string) error
+	// RecordFailure counts a failed attempt; userID is 0 for unknown emails
+	RecordFailure(ctx context.Context, email string, userID uint, clientIP string) error
+	RecordSuccess(ctx context.Context, email string) error
+	// Unlock lifts the lockout and resets the failure count of an account
+	Unlock(ctx context.Context, email string, userID uint, actor *dto.Actor) error
+}
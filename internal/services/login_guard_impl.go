@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// loginBackoffAfter failed attempts start the backoff, which doubles from loginBackoffBase up to loginBackoffMax
+	loginBackoffAfter = 3
+	loginBackoffBase  = time.Second
+	loginBackoffMax   = time.Minute
+	// loginLockRetention keeps expired lockouts around long enough to record their unlock
+	loginLockRetention = 24 * time.Hour
+)
+
+// loginLock is the lockout of an account as stored in Redis
+type loginLock struct {
+	UserID uint      `json:"user_id"`
+	Until  time.Time `json:"until"`
+}
+
+// LoginGuardImpl struct
+type LoginGuardImpl struct {
+	redisService     *RedisService
+	auditService     AuditService
+	lockoutThreshold int64
+	lockoutDuration  time.Duration
+	failureWindow    time.Duration
+	ipThreshold      int64
+}
+
+// NewLoginGuard initializes login guard
+func NewLoginGuard(redisService *RedisService, auditService AuditService) LoginGuard {
+	cfg := config.LoadConfig()
+	return &LoginGuardImpl{
+		redisService:     redisService,
+		auditService:     auditService,
+		lockoutThreshold: int64(cfg.LoginLockoutThreshold),
+		lockoutDuration:  cfg.LoginLockoutDuration,
+		failureWindow:    cfg.LoginFailureWindow,
+		ipThreshold:      int64(cfg.LoginIPThreshold),
+	}
+}
+
+// Check func
+func (g *LoginGuardImpl) Check(ctx context.Context, email string, clientIP string) error {
+	account := loginAccountKey(email)
+	now := time.Now()
+
+	var lock loginLock
+	if err := g.redisService.GetEntity(ctx, "login_lock", account, &lock); err != nil {
+		return err
+	}
+	if !lock.Until.IsZero() {
+		if now.Before(lock.Until) {
+			return &LoginThrottledError{RetryAfter: lock.Until.Sub(now)}
+		}
+		// The lockout ran out: record the unlock on the first attempt after it
+		if err := g.redisService.DeleteEntity(ctx, "login_lock", account); err != nil {
+			return err
+		}
+		if lock.UserID != 0 {
+			g.auditService.Record(ctx, systemActor, AuditActionUserUnlock, "user", lock.UserID, "lockout expired")
+		}
+	}
+
+	var backoffUntil time.Time
+	if err := g.redisService.GetEntity(ctx, "login_backoff", account, &backoffUntil); err != nil {
+		return err
+	}
+	if now.Before(backoffUntil) {
+		return &LoginThrottledError{RetryAfter: backoffUntil.Sub(now)}
+	}
+
+	var ipFailures int64
+	if err := g.redisService.GetEntity(ctx, "login_fail_ip", clientIP, &ipFailures); err != nil {
+		return err
+	}
+	if g.ipThreshold > 0 && ipFailures >= g.ipThreshold {
+		return &LoginThrottledError{RetryAfter: g.failureWindow}
+	}
+	return nil
+}
+
+// RecordFailure func
+func (g *LoginGuardImpl) RecordFailure(ctx context.Context, email string, userID uint, clientIP string) error {
+	account := loginAccountKey(email)
+
+	if _, err := g.redisService.Increment(ctx, "login_fail_ip", clientIP, g.failureWindow); err != nil {
+		return err
+	}
+	failures, err := g.redisService.Increment(ctx, "login_fail", account, g.failureWindow)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if g.lockoutThreshold > 0 && failures >= g.lockoutThreshold {
+		lock := loginLock{UserID: userID, Until: now.Add(g.lockoutDuration)}
+		if err := g.redisService.SetEntity(ctx, "login_lock", account, lock, g.lockoutDuration+loginLockRetention); err != nil {
+			return err
+		}
+		// Start counting afresh once the lockout is over
+		if err := g.redisService.DeleteEntity(ctx, "login_fail", account); err != nil {
+			return err
+		}
+		if userID != 0 {
+			g.auditService.Record(ctx, systemActor, AuditActionUserLockout, "user", userID,
+				strconv.FormatInt(failures, 10)+" failed logins, locked until "+lock.Until.UTC().Format(time.RFC3339))
+		}
+		return nil
+	}
+
+	if failures >= loginBackoffAfter {
+		backoff := loginBackoff(failures)
+		return g.redisService.SetEntity(ctx, "login_backoff", account, now.Add(backoff), backoff)
+	}
+	return nil
+}
+
+// RecordSuccess func
+func (g *LoginGuardImpl) RecordSuccess(ctx context.Context, email string) error {
+	return g.redisService.DeleteEntity(ctx, "login_fail", loginAccountKey(email))
+}
+
+// Unlock func
+func (g *LoginGuardImpl) Unlock(ctx context.Context, email string, userID uint, actor *dto.Actor) error {
+	account := loginAccountKey(email)
+	for _, entityType := range []string{"login_lock", "login_backoff", "login_fail"} {
+		if err := g.redisService.DeleteEntity(ctx, entityType, account); err != nil {
+			return err
+		}
+	}
+	g.auditService.Record(ctx, actor, AuditActionUserUnlock, "user", userID, "unlocked by admin")
+	return nil
+}
+
+// loginBackoff is the wait after the given number of failures: loginBackoffBase doubling per failure
+func loginBackoff(failures int64) time.Duration {
+	backoff := loginBackoffBase
+	for i := int64(loginBackoffAfter); i < failures && backoff < loginBackoffMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, loginBackoffMax)
+}
+
+// loginAccountKey identifies an account in Redis without storing its email in clear
+func loginAccountKey(email string) string {
+	return helpers.HashToken(strings.ToLower(strings.TrimSpace(email)))
+}
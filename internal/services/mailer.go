@@ -0,0 +1,15 @@
+package services
+
+import "context"
+
+// MailMessage is a plain-text email
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, message *MailMessage) error
+}
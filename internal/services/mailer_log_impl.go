@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+)
+
+// LogMailer does not send anything: it appends every email to a file, or writes it to the log when no
+// file is configured. It is meant for local development and tests.
+type LogMailer struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+// NewLogMailer initializes log mailer
+func NewLogMailer(path string, from string) *LogMailer {
+	return &LogMailer{path: path, from: from}
+}
+
+// Send func
+func (m *LogMailer) Send(ctx context.Context, message *MailMessage) error {
+	rendered := formatMailMessage(m.from, message)
+	if m.path == "" {
+		log.Printf("mail sink:\n%s", rendered)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(rendered, "\r\n\r\n"...))
+	return err
+}
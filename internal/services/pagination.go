@@ -0,0 +1,100 @@
+package services
+
+import (
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"time"
+)
+
+// preparePagination applies the paging defaults and the default ordering of the spec, and decodes the
+// cursor of a list. Lists sorted on a single keyset column get cursors, any other ordering keeps plain
+// page/page_size paging. A cursor carries its own ordering, so the sort may be left out when following one.
+// Cursors are verified with cursorSecret.
+func preparePagination(params *dto.ListQuery, spec *dto.ListSpec, cursorSecret []byte) error {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 10 // Default page size
+	}
+
+	var cursor *dto.Cursor
+	if params.Cursor != "" {
+		decoded, err := helpers.DecodeCursor(params.Cursor, cursorSecret)
+		if err != nil {
+			return err
+		}
+		cursor = decoded
+		if len(params.Sort) == 0 {
+			if params.Sort, err = helpers.ParseSort(spec, cursor.Sort); err != nil {
+				return helpers.ErrInvalidCursor
+			}
+		}
+	}
+
+	if len(params.Sort) == 0 {
+		sort, err := helpers.ParseSort(spec, spec.DefaultSort)
+		if err != nil {
+			return err
+		}
+		params.Sort = sort
+	}
+	params.KeysetSort = helpers.IsKeysetSort(spec, params.Sort)
+
+	if cursor != nil {
+		if !params.KeysetSort || cursor.Sort != helpers.FormatSort(params.Sort) {
+			return helpers.ErrInvalidCursor
+		}
+		params.Keyset = cursor
+	}
+	return nil
+}
+
+// keysetPage trims the extra row fetched by the repository, restores the order of a backward page and
+// builds the cursors of the neighbouring pages, signed with cursorSecret. key returns the sort value and ID of a row.
+func keysetPage[T any](rows []T, params *dto.ListQuery, cursorSecret []byte, key func(T) (time.Time, uint)) ([]T, *dto.PageCursors) {
+	cursors := &dto.PageCursors{}
+	if !params.KeysetSort {
+		return rows, cursors
+	}
+
+	hasMore := len(rows) > params.PageSize
+	if hasMore {
+		rows = rows[:params.PageSize]
+	}
+
+	backward := params.Keyset != nil && params.Keyset.Backward
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows, cursors
+	}
+
+	// Going backward, the rows beyond the page are the earlier ones
+	hasNext, hasPrev := hasMore, params.Keyset != nil || params.Page > 1
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		value, id := key(rows[len(rows)-1])
+		cursors.Next = encodePageCursor(params, cursorSecret, value, id, false)
+	}
+	if hasPrev {
+		value, id := key(rows[0])
+		cursors.Prev = encodePageCursor(params, cursorSecret, value, id, true)
+	}
+	return rows, cursors
+}
+
+// encodePageCursor signs a cursor positioned on the given row
+func encodePageCursor(params *dto.ListQuery, cursorSecret []byte, value time.Time, id uint, backward bool) string {
+	cursor := &dto.Cursor{Sort: helpers.FormatSort(params.Sort), ID: id, Backward: backward}
+	if params.Sort[0].Column != "id" {
+		cursor.Value = value.UTC().Format(time.RFC3339Nano)
+	}
+	return helpers.EncodeCursor(cursor, cursorSecret)
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+)
+
+var testCursorSecret = []byte("cursor secret")
+
+// pageRow is a list row sorted by its creation time
+type pageRow struct {
+	id        uint
+	createdAt time.Time
+}
+
+func pageRowKey(row pageRow) (time.Time, uint) {
+	return row.createdAt, row.id
+}
+
+// pageRows returns rows with the given IDs, created id minutes after the epoch
+func pageRows(ids ...uint) []pageRow {
+	rows := make([]pageRow, 0, len(ids))
+	for _, id := range ids {
+		rows = append(rows, pageRow{id: id, createdAt: time.Unix(0, 0).UTC().Add(time.Duration(id) * time.Minute)})
+	}
+	return rows
+}
+
+// keysetQuery is a list query sorted by -created_at, optionally following a cursor
+func keysetQuery(t *testing.T, pageSize int, cursor *dto.Cursor) *dto.ListQuery {
+	params := &dto.ListQuery{PageSize: pageSize}
+	if cursor != nil {
+		params.Cursor = helpers.EncodeCursor(cursor, testCursorSecret)
+	}
+	if err := preparePagination(params, dto.PostListSpec, testCursorSecret); err != nil {
+		t.Fatalf("preparePagination failed: %v", err)
+	}
+	return params
+}
+
+// decodePageCursor decodes a cursor of the page, nil when there is none
+func decodePageCursor(t *testing.T, token string) *dto.Cursor {
+	if token == "" {
+		return nil
+	}
+	cursor, err := helpers.DecodeCursor(token, testCursorSecret)
+	if err != nil {
+		t.Fatalf("DecodeCursor failed: %v", err)
+	}
+	return cursor
+}
+
+func TestKeysetPage(t *testing.T) {
+	// Rows are fetched newest first; going backward the repository reverses the order
+	tests := []struct {
+		name    string
+		cursor  *dto.Cursor
+		rows    []pageRow
+		wantIDs []uint
+		next    *dto.Cursor
+		prev    *dto.Cursor
+	}{
+		{
+			name:    "first page with more",
+			rows:    pageRows(9, 8, 7, 6),
+			wantIDs: []uint{9, 8, 7},
+			next:    &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:07:00Z", ID: 7},
+		},
+		{
+			name:    "single page",
+			rows:    pageRows(9, 8),
+			wantIDs: []uint{9, 8},
+		},
+		{
+			name:    "empty",
+			wantIDs: []uint{},
+		},
+		{
+			name:    "middle page",
+			cursor:  &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:07:00Z", ID: 7},
+			rows:    pageRows(6, 5, 4, 3),
+			wantIDs: []uint{6, 5, 4},
+			next:    &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:04:00Z", ID: 4},
+			prev:    &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:06:00Z", ID: 6, Backward: true},
+		},
+		{
+			name:    "last page",
+			cursor:  &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:04:00Z", ID: 4},
+			rows:    pageRows(3, 2),
+			wantIDs: []uint{3, 2},
+			prev:    &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:03:00Z", ID: 3, Backward: true},
+		},
+		{
+			name:    "backward with more",
+			cursor:  &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:04:00Z", ID: 4, Backward: true},
+			rows:    pageRows(5, 6, 7, 8),
+			wantIDs: []uint{7, 6, 5},
+			next:    &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:05:00Z", ID: 5},
+			prev:    &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:07:00Z", ID: 7, Backward: true},
+		},
+		{
+			name:    "backward to the first page",
+			cursor:  &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:07:00Z", ID: 7, Backward: true},
+			rows:    pageRows(8, 9),
+			wantIDs: []uint{9, 8},
+			next:    &dto.Cursor{Sort: "-created_at", Value: "1970-01-01T00:08:00Z", ID: 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := keysetQuery(t, 3, tt.cursor)
+			rows, cursors := keysetPage(tt.rows, params, testCursorSecret, pageRowKey)
+
+			ids := make([]uint, 0, len(rows))
+			for _, row := range rows {
+				ids = append(ids, row.id)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if next := decodePageCursor(t, cursors.Next); !reflect.DeepEqual(next, tt.next) {
+				t.Errorf("next = %+v, want %+v", next, tt.next)
+			}
+			if prev := decodePageCursor(t, cursors.Prev); !reflect.DeepEqual(prev, tt.prev) {
+				t.Errorf("prev = %+v, want %+v", prev, tt.prev)
+			}
+		})
+	}
+}
+
+func TestKeysetPageByID(t *testing.T) {
+	params := &dto.ListQuery{PageSize: 2, Sort: []dto.SortField{{Field: "id", Column: "id"}}, KeysetSort: true}
+	_, cursors := keysetPage(pageRows(1, 2, 3), params, testCursorSecret, pageRowKey)
+
+	// Cursors on id carry no sort value
+	want := &dto.Cursor{Sort: "id", ID: 2}
+	if next := decodePageCursor(t, cursors.Next); !reflect.DeepEqual(next, want) {
+		t.Errorf("next = %+v, want %+v", next, want)
+	}
+}
+
+func TestKeysetPageWithoutKeysetSort(t *testing.T) {
+	params := &dto.ListQuery{Page: 2, PageSize: 2, Sort: []dto.SortField{{Field: "title", Column: "title"}}}
+	rows, cursors := keysetPage(pageRows(1, 2, 3), params, testCursorSecret, pageRowKey)
+	if len(rows) != 3 || cursors.Next != "" || cursors.Prev != "" {
+		t.Errorf("offset pages must be left alone, got %d rows and cursors %+v", len(rows), cursors)
+	}
+}
+
+func TestPreparePagination(t *testing.T) {
+	params := &dto.ListQuery{}
+	if err := preparePagination(params, dto.PostListSpec, testCursorSecret); err != nil {
+		t.Fatal(err)
+	}
+	if params.Page != 1 || params.PageSize != 10 || helpers.FormatSort(params.Sort) != "-created_at" || !params.KeysetSort {
+		t.Errorf("defaults not applied: %+v", params)
+	}
+
+	// A cursor brings its own ordering
+	params = &dto.ListQuery{Cursor: helpers.EncodeCursor(&dto.Cursor{Sort: "updated_at", Value: "1970-01-01T00:01:00Z", ID: 1}, testCursorSecret)}
+	if err := preparePagination(params, dto.PostListSpec, testCursorSecret); err != nil {
+		t.Fatal(err)
+	}
+	if helpers.FormatSort(params.Sort) != "updated_at" || params.Keyset == nil || params.Keyset.ID != 1 {
+		t.Errorf("cursor not applied: %+v", params)
+	}
+}
+
+func TestPreparePaginationRejectsCursors(t *testing.T) {
+	sortByTitle, _ := helpers.ParseSort(dto.PostListSpec, "title")
+	sortByID, _ := helpers.ParseSort(dto.PostListSpec, "id")
+	tests := []struct {
+		name   string
+		cursor string
+		sort   []dto.SortField
+	}{
+		{"forged", helpers.EncodeCursor(&dto.Cursor{Sort: "-created_at", ID: 1}, []byte("other secret")), nil},
+		{"unsigned with an empty key", helpers.EncodeCursor(&dto.Cursor{Sort: "-created_at", ID: 1}, nil), nil},
+		{"other ordering", helpers.EncodeCursor(&dto.Cursor{Sort: "-created_at", ID: 1}, testCursorSecret), sortByID},
+		{"ordering without keyset", helpers.EncodeCursor(&dto.Cursor{Sort: "title", ID: 1}, testCursorSecret), sortByTitle},
+		{"unknown ordering", helpers.EncodeCursor(&dto.Cursor{Sort: "password_hash", ID: 1}, testCursorSecret), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &dto.ListQuery{Cursor: tt.cursor, Sort: tt.sort}
+			if err := preparePagination(params, dto.PostListSpec, testCursorSecret); !errors.Is(err, helpers.ErrInvalidCursor) {
+				t.Errorf("preparePagination = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+// PostRevisionService interface
+type PostRevisionService interface {
+	GetAll(ctx context.Context, postID uint, actor *dto.Actor) ([]*dto.PostRevisionResponse, error)
+	GetByRevision(ctx context.Context, postID uint, revision uint, actor *dto.Actor) (*dto.PostRevisionResponse, error)
+	Diff(ctx context.Context, postID uint, fromRevision uint, toRevision uint, actor *dto.Actor) (*dto.PostRevisionDiffResponse, error)
+	Restore(ctx context.Context, postID uint, revision uint, actor *dto.Actor) (*dto.PostResponse, error)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"html"
+)
+
+// PostRevisionServiceImpl struct
+type PostRevisionServiceImpl struct {
+	revisionRepo repositories.PostRevisionRepository
+	postRepo     repositories.PostRepository
+	postService  PostService
+}
+
+// NewPostRevisionService initializes post revision service
+func NewPostRevisionService(revisionRepo repositories.PostRevisionRepository, postRepo repositories.PostRepository, postService PostService) PostRevisionService {
+	return &PostRevisionServiceImpl{
+		revisionRepo: revisionRepo,
+		postRepo:     postRepo,
+		postService:  postService,
+	}
+}
+
+// GetAll lists the revisions of a post, newest first
+func (s *PostRevisionServiceImpl) GetAll(ctx context.Context, postID uint, actor *dto.Actor) ([]*dto.PostRevisionResponse, error) {
+	if err := s.checkPostAccess(ctx, postID, actor); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.FindAllByPostID(ctx, postID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve revisions", err)
+	}
+
+	revisionResponses := make([]*dto.PostRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		revisionResponses = append(revisionResponses, revision.ToPostRevisionResponse())
+	}
+
+	return revisionResponses, nil
+}
+
+// GetByRevision retrieves a single revision of a post
+func (s *PostRevisionServiceImpl) GetByRevision(ctx context.Context, postID uint, revision uint, actor *dto.Actor) (*dto.PostRevisionResponse, error) {
+	if err := s.checkPostAccess(ctx, postID, actor); err != nil {
+		return nil, err
+	}
+
+	postRevision, err := s.findRevision(ctx, postID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return postRevision.ToPostRevisionResponse(), nil
+}
+
+// Diff compares two revisions of a post line by line
+func (s *PostRevisionServiceImpl) Diff(ctx context.Context, postID uint, fromRevision uint, toRevision uint, actor *dto.Actor) (*dto.PostRevisionDiffResponse, error) {
+	if err := s.checkPostAccess(ctx, postID, actor); err != nil {
+		return nil, err
+	}
+
+	from, err := s.findRevision(ctx, postID, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevision(ctx, postID, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PostRevisionDiffResponse{
+		PostID:       postID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Title:        helpers.LineDiff(html.EscapeString(from.Title), html.EscapeString(to.Title)),
+		Content:      helpers.LineDiff(from.Content, to.Content),
+	}, nil
+}
+
+// Restore copies the title and content of an old revision back onto the post. The restore is
+// itself an update, so it produces a new revision and earlier history is never rewritten.
+func (s *PostRevisionServiceImpl) Restore(ctx context.Context, postID uint, revision uint, actor *dto.Actor) (*dto.PostResponse, error) {
+	if err := s.checkPostAccess(ctx, postID, actor); err != nil {
+		return nil, err
+	}
+
+	postRevision, err := s.findRevision(ctx, postID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postService.Update(ctx, postID, &dto.PostRequest{
+		Title:         postRevision.Title,
+		Content:       postRevision.Content,
+		ContentFormat: postRevision.ContentFormat,
+	}, actor)
+}
+
+// checkPostAccess applies the same ownership rules as editing the post
+func (s *PostRevisionServiceImpl) checkPostAccess(ctx context.Context, postID uint, actor *dto.Actor) error {
+	post, err := s.postRepo.FindByID(ctx, postID)
+	if err != nil {
+		return apperrors.Unavailable("failed to find post in database", err)
+	}
+	if post == nil {
+		return ErrPostNotFound
+	}
+	if !canModifyPost(post, actor, entities.PermissionPostUpdateAny) {
+		return apperrors.Forbidden("permission_denied", "you do not have permission to view the revisions of this post")
+	}
+	return nil
+}
+
+// findRevision retrieves a revision or fails when it does not exist
+func (s *PostRevisionServiceImpl) findRevision(ctx context.Context, postID uint, revision uint) (*entities.PostRevision, error) {
+	postRevision, err := s.revisionRepo.FindByPostIDAndRevision(ctx, postID, revision)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve revision", err)
+	}
+	if postRevision == nil {
+		return nil, apperrors.NotFound("revision_not_found", "revision not found")
+	}
+	return postRevision, nil
+}
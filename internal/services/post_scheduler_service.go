@@ -0,0 +1,8 @@
+package services
+
+import "context"
+
+// PostSchedulerService interface
+type PostSchedulerService interface {
+	PublishDuePosts(ctx context.Context) (int, error)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"time"
+)
+
+const (
+	postSchedulerLock      = "post_scheduler"
+	postSchedulerLockTTL   = 30 * time.Second
+	postSchedulerBatchSize = 100
+)
+
+// PostSchedulerServiceImpl struct
+type PostSchedulerServiceImpl struct {
+	postRepo     repositories.PostRepository
+	redisService *RedisService
+}
+
+// NewPostSchedulerService initializes post scheduler service
+func NewPostSchedulerService(postRepo repositories.PostRepository, redisService *RedisService) PostSchedulerService {
+	return &PostSchedulerServiceImpl{
+		postRepo:     postRepo,
+		redisService: redisService,
+	}
+}
+
+// PublishDuePosts publishes scheduled posts whose publication time has passed. A Redis lock
+// keeps replicas from doing the same work, and each post is flipped with a conditional update
+// so a post is published exactly once even if the lock expires mid-run.
+func (s *PostSchedulerServiceImpl) PublishDuePosts(ctx context.Context) (int, error) {
+	token, acquired, err := s.redisService.AcquireLock(ctx, postSchedulerLock, postSchedulerLockTTL)
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		return 0, nil
+	}
+	defer func() {
+		_ = s.redisService.ReleaseLock(ctx, postSchedulerLock, token)
+	}()
+
+	posts, err := s.postRepo.FindDueScheduled(ctx, time.Now(), postSchedulerBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, post := range posts {
+		ok, err := s.postRepo.PublishScheduled(ctx, post.ID)
+		if err != nil {
+			return published, err
+		}
+		if !ok {
+			continue
+		}
+		published++
+
+		// Drop the cached copy so readers see the new status
+		idStr, _ := helpers.ConvertToString(post.ID)
+		_ = s.redisService.DeleteEntity(ctx, "post", idStr)
+	}
+
+	return published, nil
+}
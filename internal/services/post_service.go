@@ -1,15 +1,23 @@
 package services
 
 import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 )
 
+// ErrPostNotFound is returned for posts that do not exist or are not visible to the viewer
+var ErrPostNotFound = apperrors.NotFound("post_not_found", "post not found")
+
 // PostService interface
 type PostService interface {
-	CreatePost(postRequest *dto.PostRequest) (*dto.PostResponse, error)
-	GetPostByID(id uint) (*dto.PostResponse, error)
-	GetAll(params *dto.QueryParams) ([]*dto.PostResponse, error)
-	Update(id uint, postRequest *dto.PostRequest) (*dto.PostResponse, error)
-	Delete(id uint, userID uint) error
-	Count(params *dto.QueryParams) (int64, error)
+	CreatePost(ctx context.Context, postRequest *dto.PostRequest) (*dto.PostResponse, error)
+	GetPostByID(ctx context.Context, id uint, viewerID uint) (*dto.PostResponse, error)
+	GetPostBySlug(ctx context.Context, slug string, viewerID uint) (*dto.PostResponse, error)
+	GetAll(ctx context.Context, params *dto.ListQuery) ([]*dto.PostResponse, *dto.PageCursors, error)
+	Update(ctx context.Context, id uint, postRequest *dto.PostRequest, actor *dto.Actor) (*dto.PostResponse, error)
+	Delete(ctx context.Context, id uint, actor *dto.Actor) error
+	Count(ctx context.Context, params *dto.ListQuery) (int64, error)
+	Publish(ctx context.Context, id uint, publishRequest *dto.PublishPostRequest, actor *dto.Actor) (*dto.PostResponse, error)
+	Unpublish(ctx context.Context, id uint, actor *dto.Actor) (*dto.PostResponse, error)
 }
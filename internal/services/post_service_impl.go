@@ -1,153 +1,327 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 	"github.com/dedenfarhanhub/blog-service/internal/entities"
 	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/metrics"
 	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// maxSlugAttempts is how many numbered variants of a slug are tried before falling back to a random suffix
+const maxSlugAttempts = 20
+
 // PostServiceImpl struct
 type PostServiceImpl struct {
-	postRepo     repositories.PostRepository
-	userService  UserService
-	redisService *RedisService
+	postRepo      repositories.PostRepository
+	tagRepo       repositories.TagRepository
+	categoryRepo  repositories.CategoryRepository
+	searchService SearchService
+	userService   UserService
+	auditService  AuditService
+	redisService  *RedisService
+	cursorSecret  []byte
 }
 
 // CreatePost creates a new post
-func (s *PostServiceImpl) CreatePost(postRequest *dto.PostRequest) (*dto.PostResponse, error) {
+func (s *PostServiceImpl) CreatePost(ctx context.Context, postRequest *dto.PostRequest) (*dto.PostResponse, error) {
 	if err := s.validatePostRequest(postRequest); err != nil {
 		return nil, err
 	}
 
-	author, err := s.userService.FindAuthorByID(postRequest.AuthorID)
+	author, err := s.userService.FindAuthorByID(ctx, postRequest.AuthorID)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, ErrAuthorNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if author == nil {
-		return nil, errors.New("author not found")
-	}
-
 	postEntity := s.newPostEntity(postRequest, author)
+	if postEntity.Slug, err = s.uniqueSlug(ctx, postEntity.Title, 0); err != nil {
+		return nil, err
+	}
+	if postRequest.CommentPolicy != "" {
+		postEntity.CommentPolicy = postRequest.CommentPolicy
+	}
+	if err := applyPostStatus(postEntity, postRequest.Status, postRequest.PublishAt); err != nil {
+		return nil, err
+	}
+	if err := s.applyTaxonomy(ctx, postEntity, postRequest); err != nil {
+		return nil, err
+	}
 
-	if err := s.createPost(postEntity); err != nil {
+	if err := s.createPost(ctx, postEntity); err != nil {
 		return nil, err
 	}
+	metrics.PostsCreated.Inc()
 
-	if err := s.cachePost(postEntity); err != nil {
+	// The post is saved: finish its cache entry even if the client goes away
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
+	if err := s.cachePost(ctx, postEntity); err != nil {
 		return nil, err
 	}
+	s.searchService.Index(postEntity)
 
-	return postEntity.ToPostResponse(author.ToAuthorResponse()), nil
+	return s.toPostResponse(ctx, postEntity), nil
 }
 
-// GetPostByID retrieves a post by its ID
-func (s *PostServiceImpl) GetPostByID(id uint) (*dto.PostResponse, error) {
-	post, err := s.getPostFromCache(id)
+// GetPostByID retrieves a post by its ID. Posts that are not public are only returned to their author.
+func (s *PostServiceImpl) GetPostByID(ctx context.Context, id uint, viewerID uint) (*dto.PostResponse, error) {
+	post, err := s.getPostFromCache(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if post == nil {
-		post, err = s.getPostFromDatabase(id)
+		post, err = s.getPostFromDatabase(ctx, id)
 		if err != nil {
 			return nil, err
 		}
 		if post == nil {
-			return nil, errors.New("post not found")
+			return nil, ErrPostNotFound
 		}
 
-		if err := s.cachePost(post); err != nil {
+		if err := s.cachePost(ctx, post); err != nil {
 			return nil, err
 		}
 	}
 
-	return post.ToPostResponse(post.Author.ToAuthorResponse()), nil
+	if !post.IsVisibleTo(viewerID) {
+		return nil, ErrPostNotFound
+	}
+
+	return s.toPostResponse(ctx, post), nil
 }
 
-// GetAll func
-func (s *PostServiceImpl) GetAll(params *dto.QueryParams) ([]*dto.PostResponse, error) {
-	if params.Page < 1 {
-		params.Page = 1
+// GetPostBySlug retrieves a post by its current slug or by one of its previous slugs. The slug is
+// resolved to an ID through Redis, then the post is loaded like GetPostByID. Callers can compare the
+// requested slug with the returned one to redirect to the canonical link.
+func (s *PostServiceImpl) GetPostBySlug(ctx context.Context, slug string, viewerID uint) (*dto.PostResponse, error) {
+	var id uint
+	if err := s.redisService.GetEntity(ctx, "post_slug", slug, &id); err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve post from Redis", err)
+	}
+
+	if id == 0 {
+		var err error
+		id, err = s.postRepo.FindIDBySlug(ctx, slug)
+		if err != nil {
+			return nil, apperrors.Unavailable("failed to find post in database", err)
+		}
+		if id == 0 {
+			return nil, ErrPostNotFound
+		}
+
+		if err := s.redisService.SetEntity(ctx, "post_slug", slug, id, 24*time.Hour); err != nil {
+			return nil, apperrors.Unavailable("failed to store post in Redis", err)
+		}
 	}
-	if params.PageSize < 1 {
-		params.PageSize = 10 // Default page size
+
+	return s.GetPostByID(ctx, id, viewerID)
+}
+
+// GetAll func
+func (s *PostServiceImpl) GetAll(ctx context.Context, params *dto.ListQuery) ([]*dto.PostResponse, *dto.PageCursors, error) {
+	// Newest first unless asked otherwise
+	if err := preparePagination(params, dto.PostListSpec, s.cursorSecret); err != nil {
+		return nil, nil, err
 	}
 
-	posts, err := s.postRepo.FindAllWithFilters(params)
+	posts, err := s.postRepo.FindAllWithFilters(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, apperrors.Unavailable("failed to retrieve posts", err)
 	}
 
+	posts, cursors := keysetPage(posts, params, s.cursorSecret, func(post entities.Post) (time.Time, uint) {
+		if params.Sort[0].Field == "updated_at" {
+			return post.UpdatedAt, post.ID
+		}
+		return post.CreatedAt, post.ID
+	})
+
 	var postResponses []*dto.PostResponse
 	for _, post := range posts {
-		postResponses = append(postResponses, post.ToPostResponse(post.Author.ToAuthorResponse()))
+		postResponses = append(postResponses, s.toPostResponse(ctx, &post))
 	}
 
-	return postResponses, nil
+	return postResponses, cursors, nil
 }
 
 // Update func
-func (s *PostServiceImpl) Update(id uint, postRequest *dto.PostRequest) (*dto.PostResponse, error) {
-	existingPost, err := s.getPostWithOwnershipCheck(id, postRequest.AuthorID)
+func (s *PostServiceImpl) Update(ctx context.Context, id uint, postRequest *dto.PostRequest, actor *dto.Actor) (*dto.PostResponse, error) {
+	existingPost, err := s.getPostWithOwnershipCheck(ctx, id, actor, entities.PermissionPostUpdateAny)
 	if err != nil {
 		return nil, err
 	}
 
+	previousSlug := existingPost.Slug
+	if !slugMatchesTitle(existingPost.Slug, postRequest.Title) {
+		if existingPost.Slug, err = s.uniqueSlug(ctx, postRequest.Title, existingPost.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	existingPost.Title = postRequest.Title
 	existingPost.Content = postRequest.Content
+	if postRequest.ContentFormat != "" {
+		existingPost.ContentFormat = postRequest.ContentFormat
+	}
 	existingPost.UpdatedAt = time.Now()
-	if err := s.updatePost(existingPost); err != nil {
+	if postRequest.CommentPolicy != "" {
+		existingPost.CommentPolicy = postRequest.CommentPolicy
+	}
+	if postRequest.Status != "" {
+		if err := applyPostStatus(existingPost, postRequest.Status, postRequest.PublishAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.applyTaxonomy(ctx, existingPost, postRequest); err != nil {
 		return nil, err
 	}
-	if err := s.cachePost(existingPost); err != nil {
+	if err := s.updatePost(ctx, existingPost, newRevision(existingPost, actor.ID)); err != nil {
 		return nil, err
 	}
 
-	return existingPost.ToPostResponse(existingPost.Author.ToAuthorResponse()), nil
+	// The post and its revision are saved: finish the cache and audit entry even if the client goes away
+	ctx, cancel := detach(ctx)
+	defer cancel()
+	if previousSlug != "" && previousSlug != existingPost.Slug {
+		// Keep the old slug so existing links redirect to the new one
+		if err := s.postRepo.AddSlugAlias(ctx, existingPost.ID, previousSlug, existingPost.Slug); err != nil {
+			return nil, apperrors.Unavailable("failed to keep the previous post slug", err)
+		}
+	}
+	if err := s.cachePost(ctx, existingPost); err != nil {
+		return nil, err
+	}
+	s.searchService.Index(existingPost)
+
+	if existingPost.AuthorID != actor.ID {
+		s.auditService.Record(ctx, actor, AuditActionPostUpdate, "post", existingPost.ID, "")
+	}
+
+	return s.toPostResponse(ctx, existingPost), nil
 }
 
 // Delete func
-func (s *PostServiceImpl) Delete(id uint, userID uint) error {
-	existingPost, err := s.getPostWithOwnershipCheck(id, userID)
+func (s *PostServiceImpl) Delete(ctx context.Context, id uint, actor *dto.Actor) error {
+	existingPost, err := s.getPostWithOwnershipCheck(ctx, id, actor, entities.PermissionPostDeleteAny)
 	if err != nil {
 		return err
 	}
 
+	slugs, err := s.postRepo.FindSlugAliases(ctx, existingPost.ID)
+	if err != nil {
+		return apperrors.Unavailable("failed to find post slugs", err)
+	}
+
 	// Delete the post from the database
-	if err := s.postRepo.Delete(existingPost.ID); err != nil {
-		return err
+	if err := s.postRepo.Delete(ctx, existingPost.ID); err != nil {
+		return apperrors.Unavailable("failed to delete post", err)
 	}
 
-	// Remove the post from Redis cache
-	idStr, _ := helpers.ConvertToString(existingPost.ID)
-	if err := s.redisService.DeleteEntity("post", idStr); err != nil {
+	// The post is gone: clear its cache even if the client goes away
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
+	if err := evictPost(ctx, s.redisService, s.searchService, existingPost.ID, append(slugs, existingPost.Slug)); err != nil {
 		return err
 	}
+
+	if existingPost.AuthorID != actor.ID {
+		s.auditService.Record(ctx, actor, AuditActionPostDelete, "post", existingPost.ID, "title: "+existingPost.Title)
+	}
 	return nil
 }
 
 // Count func
-func (s *PostServiceImpl) Count(params *dto.QueryParams) (int64, error) {
-	return s.postRepo.Count(params)
+func (s *PostServiceImpl) Count(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	count, err := s.postRepo.Count(ctx, params)
+	if err != nil {
+		return 0, apperrors.Unavailable("failed to count posts", err)
+	}
+	return count, nil
+}
+
+// Publish publishes a post right away, or schedules it when publish_at is in the future
+func (s *PostServiceImpl) Publish(ctx context.Context, id uint, publishRequest *dto.PublishPostRequest, actor *dto.Actor) (*dto.PostResponse, error) {
+	status := entities.PostStatusPublished
+	if publishRequest.PublishAt != nil && publishRequest.PublishAt.After(time.Now()) {
+		status = entities.PostStatusScheduled
+	}
+	return s.changeStatus(ctx, id, status, publishRequest.PublishAt, actor)
+}
+
+// Unpublish moves a published or scheduled post back to draft
+func (s *PostServiceImpl) Unpublish(ctx context.Context, id uint, actor *dto.Actor) (*dto.PostResponse, error) {
+	return s.changeStatus(ctx, id, entities.PostStatusDraft, nil, actor)
 }
 
-// NewPostService initializes post service
-func NewPostService(postRepo repositories.PostRepository, userService UserService, redisService *RedisService) PostService {
+// NewPostService initializes post service. cursorSecret signs the pagination cursors.
+func NewPostService(postRepo repositories.PostRepository, tagRepo repositories.TagRepository, categoryRepo repositories.CategoryRepository, searchService SearchService, userService UserService, auditService AuditService, redisService *RedisService, cursorSecret []byte) PostService {
 	return &PostServiceImpl{
-		postRepo:     postRepo,
-		userService:  userService,
-		redisService: redisService,
+		postRepo:      postRepo,
+		tagRepo:       tagRepo,
+		categoryRepo:  categoryRepo,
+		searchService: searchService,
+		userService:   userService,
+		auditService:  auditService,
+		redisService:  redisService,
+		cursorSecret:  cursorSecret,
 	}
 }
 
+// changeStatus moves a post to a new lifecycle state after checking ownership
+func (s *PostServiceImpl) changeStatus(ctx context.Context, id uint, status string, publishAt *time.Time, actor *dto.Actor) (*dto.PostResponse, error) {
+	existingPost, err := s.getPostWithOwnershipCheck(ctx, id, actor, entities.PermissionPostUpdateAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyPostStatus(existingPost, status, publishAt); err != nil {
+		return nil, err
+	}
+	existingPost.UpdatedAt = time.Now()
+	if err := s.updatePost(ctx, existingPost, nil); err != nil {
+		return nil, err
+	}
+
+	// The post is saved: finish its cache and audit entry even if the client goes away
+	ctx, cancel := detach(ctx)
+	defer cancel()
+	if err := s.cachePost(ctx, existingPost); err != nil {
+		return nil, err
+	}
+	s.searchService.Index(existingPost)
+
+	if existingPost.AuthorID != actor.ID {
+		s.auditService.Record(ctx, actor, AuditActionPostUpdate, "post", existingPost.ID, "status: "+status)
+	}
+
+	return s.toPostResponse(ctx, existingPost), nil
+}
+
 // validatePostRequest validates the post request parameters
 func (s *PostServiceImpl) validatePostRequest(postRequest *dto.PostRequest) error {
 	if postRequest.Title == "" || postRequest.Content == "" {
-		return errors.New("title and content are required")
+		var fields []dto.FieldError
+		if postRequest.Title == "" {
+			fields = append(fields, dto.FieldError{Field: "title", Message: "is required"})
+		}
+		if postRequest.Content == "" {
+			fields = append(fields, dto.FieldError{Field: "content", Message: "is required"})
+		}
+		return apperrors.Validation("invalid_post", "title and content are required", fields...)
 	}
 	return nil
 }
@@ -155,94 +329,341 @@ func (s *PostServiceImpl) validatePostRequest(postRequest *dto.PostRequest) erro
 // newPostEntity creates a new post entity from the request and author
 func (s *PostServiceImpl) newPostEntity(postRequest *dto.PostRequest, author *entities.User) *entities.Post {
 	return &entities.Post{
-		Title:     postRequest.Title,
-		Content:   postRequest.Content,
-		AuthorID:  postRequest.AuthorID,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		Author:    author,
+		Title:         postRequest.Title,
+		Content:       postRequest.Content,
+		ContentFormat: contentFormatOrDefault(postRequest.ContentFormat),
+		AuthorID:      postRequest.AuthorID,
+		CommentPolicy: entities.CommentPolicyInherit,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		Author:        author,
+	}
+}
+
+// uniqueSlug derives a slug from the title that no other post uses, as its slug or as an alias.
+// Collisions get a numeric suffix ("my-post-2").
+func (s *PostServiceImpl) uniqueSlug(ctx context.Context, title string, postID uint) (string, error) {
+	base := helpers.Slugify(title)
+	if base == "" {
+		base = "post"
+	}
+
+	for attempt := 1; attempt <= maxSlugAttempts; attempt++ {
+		candidate := base
+		if attempt > 1 {
+			candidate = base + "-" + strconv.Itoa(attempt)
+		}
+		taken, err := s.postRepo.SlugTaken(ctx, candidate, postID)
+		if err != nil {
+			return "", apperrors.Unavailable("failed to check post slug", err)
+		}
+		if !taken {
+			return candidate, nil
+		}
 	}
+
+	// Very common titles: fall back to a suffix that is unique in practice
+	return base + "-" + strconv.FormatInt(time.Now().UnixMilli(), 10), nil
 }
 
-// createPost crate the post to the database
-func (s *PostServiceImpl) createPost(postEntity *entities.Post) error {
-	if err := s.postRepo.Create(postEntity); err != nil {
-		return errors.New("failed to create post")
+// slugMatchesTitle reports whether the slug was derived from the title, possibly with a collision
+// suffix, so an update that does not change the slug-relevant part of the title keeps the slug
+func slugMatchesTitle(slug string, title string) bool {
+	base := helpers.Slugify(title)
+	if base == "" {
+		base = "post"
+	}
+	if slug == base {
+		return true
 	}
+	suffix, found := strings.CutPrefix(slug, base+"-")
+	if !found {
+		return false
+	}
+	number, err := strconv.ParseInt(suffix, 10, 64)
+	if err != nil {
+		return false
+	}
+	// Numbered variants, or the timestamp fallback
+	return (number >= 2 && number <= maxSlugAttempts) || len(suffix) >= 13
+}
+
+// applyTaxonomy resolves the requested tags and categories onto the post. A list left out of the
+// request keeps the post's current tags or categories.
+func (s *PostServiceImpl) applyTaxonomy(ctx context.Context, postEntity *entities.Post, postRequest *dto.PostRequest) error {
+	if postRequest.Tags != nil {
+		tags, err := s.resolveTags(ctx, postRequest.Tags)
+		if err != nil {
+			return err
+		}
+		postEntity.Tags = tags
+	}
+
+	if postRequest.Categories != nil {
+		categories, err := s.resolveCategories(ctx, postRequest.Categories)
+		if err != nil {
+			return err
+		}
+		postEntity.Categories = categories
+	}
+
 	return nil
 }
 
-// updatePost update the post to the database
-func (s *PostServiceImpl) updatePost(postEntity *entities.Post) error {
-	if err := s.postRepo.Update(postEntity); err != nil {
-		return errors.New("failed to create post")
+// resolveTags finds the tags by slug, creating the ones used for the first time
+func (s *PostServiceImpl) resolveTags(ctx context.Context, names []string) ([]*entities.Tag, error) {
+	seen := make(map[string]bool, len(names))
+	tags := make([]entities.Tag, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		slug := helpers.Slugify(name)
+		if slug == "" {
+			return nil, apperrors.Validation("invalid_tag", fmt.Sprintf("tag %q must contain letters or digits", name),
+				dto.FieldError{Field: "tags", Message: "must contain letters or digits"})
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tags = append(tags, entities.Tag{Name: name, Slug: slug})
+	}
+
+	resolved, err := s.tagRepo.FindOrCreate(ctx, tags)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to save tags", err)
+	}
+	return resolved, nil
+}
+
+// resolveCategories finds the categories by slug; every category must already exist
+func (s *PostServiceImpl) resolveCategories(ctx context.Context, slugs []string) ([]*entities.Category, error) {
+	seen := make(map[string]bool, len(slugs))
+	normalized := make([]string, 0, len(slugs))
+	for _, slug := range slugs {
+		slug = helpers.Slugify(slug)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		normalized = append(normalized, slug)
+	}
+
+	categories, err := s.categoryRepo.FindBySlugs(ctx, normalized)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to find categories", err)
+	}
+
+	found := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		found[category.Slug] = true
+	}
+	for _, slug := range normalized {
+		if !found[slug] {
+			return nil, apperrors.Validation("unknown_category", fmt.Sprintf("category %q does not exist", slug),
+				dto.FieldError{Field: "categories", Message: fmt.Sprintf("category %q does not exist", slug)})
+		}
+	}
+
+	return categories, nil
+}
+
+// createPost crate the post to the database, with its first revision
+func (s *PostServiceImpl) createPost(ctx context.Context, postEntity *entities.Post) error {
+	if err := s.postRepo.Create(ctx, postEntity, newRevision(postEntity, postEntity.AuthorID)); err != nil {
+		return dependencyError("failed to create post", err)
 	}
 	return nil
 }
 
+// updatePost update the post to the database, recording the revision when one is given
+func (s *PostServiceImpl) updatePost(ctx context.Context, postEntity *entities.Post, revision *entities.PostRevision) error {
+	if err := s.postRepo.Update(ctx, postEntity, revision); err != nil {
+		return dependencyError("failed to update post", err)
+	}
+	return nil
+}
+
+// newRevision is an immutable snapshot of the post's current title, content and content format
+func newRevision(postEntity *entities.Post, editorID uint) *entities.PostRevision {
+	return &entities.PostRevision{
+		PostID:        postEntity.ID,
+		Title:         postEntity.Title,
+		Content:       postEntity.Content,
+		ContentFormat: postEntity.ContentFormat,
+		EditorID:      editorID,
+	}
+}
+
+// toPostResponse converts a post to its response, including the rendered content
+func (s *PostServiceImpl) toPostResponse(ctx context.Context, postEntity *entities.Post) *dto.PostResponse {
+	postResponse := postEntity.ToPostResponse(postEntity.Author.ToAuthorResponse())
+
+	rendered := s.renderContent(ctx, postEntity)
+	postResponse.ContentHTML = rendered.HTML
+	postResponse.Excerpt = rendered.Excerpt
+	postResponse.ReadingTime = rendered.ReadingTime
+	return postResponse
+}
+
+// renderContent renders the post content to sanitized HTML. Renderings are cached in Redis by a hash of the
+// format and content, so unchanged content is only rendered once and edits never serve a stale rendering.
+func (s *PostServiceImpl) renderContent(ctx context.Context, postEntity *entities.Post) *helpers.RenderedContent {
+	format := contentFormatOrDefault(postEntity.ContentFormat)
+	cacheKey := helpers.HashToken(format + ":" + postEntity.Content)
+
+	rendered := &helpers.RenderedContent{}
+	if err := s.redisService.GetEntity(ctx, "post_html", cacheKey, rendered); err == nil && rendered.HTML != "" {
+		return rendered
+	}
+
+	if format == entities.ContentFormatPlain {
+		rendered = helpers.RenderPlainText(postEntity.Content)
+	} else {
+		var err error
+		if rendered, err = helpers.RenderMarkdown(postEntity.Content); err != nil {
+			// Fall back to plain text rather than failing the whole request
+			rendered = helpers.RenderPlainText(postEntity.Content)
+		}
+	}
+
+	_ = s.redisService.SetEntity(ctx, "post_html", cacheKey, rendered, 7*24*time.Hour)
+	return rendered
+}
+
 // cachePost stores the post in Redis for caching
-func (s *PostServiceImpl) cachePost(postEntity *entities.Post) error {
+func (s *PostServiceImpl) cachePost(ctx context.Context, postEntity *entities.Post) error {
 	idStr, _ := helpers.ConvertToString(postEntity.ID)
-	if err := s.redisService.SetEntity("post", idStr, postEntity, 24*time.Hour); err != nil {
-		return errors.New("failed to store post in Redis")
+	if err := s.redisService.SetEntity(ctx, "post", idStr, postEntity, 24*time.Hour); err != nil {
+		return apperrors.Unavailable("failed to store post in Redis", err)
 	}
 	return nil
 }
 
 // getPostFromCache retrieves a post from Redis
-func (s *PostServiceImpl) getPostFromCache(id uint) (*entities.Post, error) {
+func (s *PostServiceImpl) getPostFromCache(ctx context.Context, id uint) (*entities.Post, error) {
 	idStr, _ := helpers.ConvertToString(id)
 	existingPost := &entities.Post{}
-	err := s.redisService.GetEntity("post", idStr, existingPost)
+	err := s.redisService.GetEntity(ctx, "post", idStr, existingPost)
 	if err != nil {
-		return nil, errors.New("failed to retrieve post from Redis")
+		return nil, apperrors.Unavailable("failed to retrieve post from Redis", err)
 	}
 	if existingPost.ID != 0 {
-		if existingPost.Author.ID == 0 {
-			// If Author is missing, we need to load it from the database
-			author, err := s.userService.FindAuthorByID(existingPost.AuthorID)
-			if err != nil {
-				return nil, errors.New("failed to load author details")
-			}
-			existingPost.Author = author
+		// Always take the author from the user cache, which is invalidated on profile changes, so the
+		// cached post never shows an outdated name or avatar
+		author, err := s.userService.FindAuthorByID(ctx, existingPost.AuthorID)
+		if errors.Is(err, ErrUserNotFound) {
+			// The author deleted their account, and the post with it: leave it to the database
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
 		}
+		existingPost.Author = author
 		return existingPost, nil
 	}
 	return nil, nil
 }
 
+// evictPost removes a deleted post from the search index, and the post and its slugs from the Redis
+// cache, so the slugs can be reused
+func evictPost(ctx context.Context, redisService *RedisService, searchService SearchService, id uint, slugs []string) error {
+	searchService.Remove(id)
+
+	idStr, _ := helpers.ConvertToString(id)
+	if err := redisService.DeleteEntity(ctx, "post", idStr); err != nil {
+		return apperrors.Unavailable("failed to remove post from Redis", err)
+	}
+	for _, slug := range slugs {
+		if err := redisService.DeleteEntity(ctx, "post_slug", slug); err != nil {
+			return apperrors.Unavailable("failed to remove post from Redis", err)
+		}
+	}
+	return nil
+}
+
 // getPostFromDatabase retrieves a post from the database
-func (s *PostServiceImpl) getPostFromDatabase(id uint) (*entities.Post, error) {
-	postFromDB, err := s.postRepo.FindByID(id)
+func (s *PostServiceImpl) getPostFromDatabase(ctx context.Context, id uint) (*entities.Post, error) {
+	postFromDB, err := s.postRepo.FindByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("failed to find post in database")
+		return nil, apperrors.Unavailable("failed to find post in database", err)
 	}
 	return postFromDB, nil
 }
 
 // getPostWithOwnershipCheck retrieves a post and checks if the user is the author
-func (s *PostServiceImpl) getPostWithOwnershipCheck(id uint, userID uint) (*entities.Post, error) {
+// or holds the permission to modify any post
+func (s *PostServiceImpl) getPostWithOwnershipCheck(ctx context.Context, id uint, actor *dto.Actor, permission entities.Permission) (*entities.Post, error) {
 	// Check Redis first
-	post, err := s.getPostFromCache(id)
+	post, err := s.getPostFromCache(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// If not found in Redis, check the database
 	if post == nil {
-		post, err = s.getPostFromDatabase(id)
+		post, err = s.getPostFromDatabase(ctx, id)
 		if err != nil {
 			return nil, err
 		}
 		if post == nil {
-			return nil, errors.New("post not found")
+			return nil, ErrPostNotFound
 		}
 	}
 
 	// Check ownership
-	if post.AuthorID != userID {
-		return nil, errors.New("you do not have permission to modify this post")
+	if !canModifyPost(post, actor, permission) {
+		return nil, apperrors.Forbidden("permission_denied", "you do not have permission to modify this post")
 	}
 
 	return post, nil
 }
+
+// contentFormatOrDefault returns the content format, markdown when none is set
+func contentFormatOrDefault(format string) string {
+	if format == "" {
+		return entities.ContentFormatMarkdown
+	}
+	return format
+}
+
+// canModifyPost reports whether the actor owns the post or holds the given "any post" permission
+func canModifyPost(post *entities.Post, actor *dto.Actor, permission entities.Permission) bool {
+	return post.AuthorID == actor.ID || entities.Role(actor.Role).HasPermission(permission)
+}
+
+// applyPostStatus validates a lifecycle transition and sets the status and publication time.
+// An empty status keeps the previous behaviour of publishing immediately.
+func applyPostStatus(post *entities.Post, status string, publishAt *time.Time) error {
+	if status == "" {
+		status = entities.PostStatusPublished
+	}
+	if !entities.IsPostStatus(status) {
+		return apperrors.Validation("invalid_status", "invalid post status")
+	}
+	if !post.CanMoveTo(status) {
+		return apperrors.Conflict("invalid_status_transition", fmt.Sprintf("a %s post cannot be made %s", post.Status, status))
+	}
+
+	now := time.Now()
+	switch status {
+	case entities.PostStatusPublished:
+		if post.Status != entities.PostStatusPublished || post.PublishedAt == nil {
+			post.PublishedAt = &now
+		}
+		post.Status = entities.PostStatusPublished
+	case entities.PostStatusScheduled:
+		if publishAt == nil || !publishAt.After(now) {
+			return apperrors.Validation("invalid_publish_at", "publish_at must be in the future to schedule a post",
+				dto.FieldError{Field: "publish_at", Message: "must be in the future"})
+		}
+		scheduledAt := publishAt.UTC()
+		post.Status = entities.PostStatusScheduled
+		post.PublishedAt = &scheduledAt
+	case entities.PostStatusDraft:
+		post.Status = entities.PostStatusDraft
+		post.PublishedAt = nil
+	case entities.PostStatusArchived:
+		post.Status = entities.PostStatusArchived
+	}
+	return nil
+}
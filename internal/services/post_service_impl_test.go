@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+)
+
+func TestApplyPostStatus(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		from      string
+		to        string
+		publishAt *time.Time
+		want      error
+	}{
+		// New posts can start in any status, publishing by default
+		{from: "", to: ""},
+		{from: "", to: entities.PostStatusDraft},
+		{from: "", to: entities.PostStatusScheduled, publishAt: &future},
+		{from: "", to: entities.PostStatusArchived},
+
+		{from: entities.PostStatusDraft, to: entities.PostStatusScheduled, publishAt: &future},
+		{from: entities.PostStatusDraft, to: entities.PostStatusPublished},
+		{from: entities.PostStatusDraft, to: entities.PostStatusArchived},
+		{from: entities.PostStatusScheduled, to: entities.PostStatusScheduled, publishAt: &future},
+		{from: entities.PostStatusScheduled, to: entities.PostStatusPublished},
+		{from: entities.PostStatusScheduled, to: entities.PostStatusDraft},
+		{from: entities.PostStatusPublished, to: entities.PostStatusPublished},
+		{from: entities.PostStatusPublished, to: entities.PostStatusDraft},
+		{from: entities.PostStatusPublished, to: entities.PostStatusArchived},
+		{from: entities.PostStatusArchived, to: entities.PostStatusDraft},
+		{from: entities.PostStatusArchived, to: entities.PostStatusPublished},
+
+		// Illegal moves
+		{from: entities.PostStatusPublished, to: entities.PostStatusScheduled, publishAt: &future, want: apperrors.ErrConflict},
+		{from: entities.PostStatusArchived, to: entities.PostStatusScheduled, publishAt: &future, want: apperrors.ErrConflict},
+		{from: entities.PostStatusScheduled, to: entities.PostStatusArchived, want: apperrors.ErrConflict},
+
+		// Invalid input
+		{from: entities.PostStatusDraft, to: entities.PostStatusScheduled, publishAt: &past, want: apperrors.ErrValidation},
+		{from: entities.PostStatusDraft, to: entities.PostStatusScheduled, want: apperrors.ErrValidation},
+		{from: entities.PostStatusDraft, to: "deleted", want: apperrors.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		post := &entities.Post{Status: tt.from}
+		err := applyPostStatus(post, tt.to, tt.publishAt)
+		if tt.want != nil {
+			if !errors.Is(err, tt.want) {
+				t.Errorf("%q -> %q: got %v, want %v", tt.from, tt.to, err, tt.want)
+			}
+			if post.Status != tt.from {
+				t.Errorf("%q -> %q: a rejected move changed the status to %q", tt.from, tt.to, post.Status)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q -> %q: unexpected error %v", tt.from, tt.to, err)
+			continue
+		}
+
+		want := tt.to
+		if want == "" {
+			want = entities.PostStatusPublished
+		}
+		if post.Status != want {
+			t.Errorf("%q -> %q: status is %q", tt.from, tt.to, post.Status)
+		}
+		if published := post.PublishedAt != nil; published != (want == entities.PostStatusPublished || want == entities.PostStatusScheduled) {
+			t.Errorf("%q -> %q: published_at is %v", tt.from, tt.to, post.PublishedAt)
+		}
+	}
+}
+
+func TestApplyPostStatusKeepsPublicationTime(t *testing.T) {
+	publishedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	post := &entities.Post{Status: entities.PostStatusPublished, PublishedAt: &publishedAt}
+	if err := applyPostStatus(post, entities.PostStatusPublished, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !post.PublishedAt.Equal(publishedAt) {
+		t.Errorf("saving a published post moved published_at to %s", post.PublishedAt)
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitPolicy lets a client make Limit requests per Period. The budget refills continuously, so a
+// client can burst up to Limit requests and then gets one more every Period/Limit.
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+// RateLimitResult is the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the full budget is available again
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request is allowed, zero when allowed
+	RetryAfter time.Duration
+}
+
+// RateLimiter checks requests against a rate limit policy, per client key
+type RateLimiter interface {
+	Allow(ctx context.Context, policy RateLimitPolicy, key string) (*RateLimitResult, error)
+}
+
+// gcra applies the generic cell rate algorithm: tat is the theoretical arrival time stored for the client.
+// It returns the new tat to store (unchanged when the request is rejected) and the result.
+func gcra(policy RateLimitPolicy, tat time.Time, now time.Time) (time.Time, *RateLimitResult) {
+	interval := policy.Period / time.Duration(policy.Limit)
+	tolerance := policy.Period
+
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval)
+	used := newTat.Sub(now)
+
+	if used > tolerance {
+		return tat, &RateLimitResult{
+			Limit:      policy.Limit,
+			ResetAfter: tat.Sub(now),
+			RetryAfter: used - tolerance,
+		}
+	}
+	return newTat, &RateLimitResult{
+		Allowed:    true,
+		Limit:      policy.Limit,
+		Remaining:  int((tolerance - used) / interval),
+		ResetAfter: used,
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemoryRateLimiter keeps the rate limit state in process memory. Each replica has its own budget,
+// so it is meant for single-instance setups and as the fallback of the Redis limiter.
+type InMemoryRateLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+}
+
+// rateLimitSweepInterval is how often expired client state is dropped
+const rateLimitSweepInterval = time.Minute
+
+// NewInMemoryRateLimiter initializes in-memory rate limiter
+func NewInMemoryRateLimiter() *InMemoryRateLimiter {
+	return &InMemoryRateLimiter{tats: make(map[string]time.Time), lastSweep: time.Now()}
+}
+
+// Allow func
+func (l *InMemoryRateLimiter) Allow(ctx context.Context, policy RateLimitPolicy, key string) (*RateLimitResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	key = policy.Name + ":" + key
+	tat, result := gcra(policy, l.tats[key], now)
+	l.tats[key] = tat
+	return result, nil
+}
+
+// sweep drops the clients whose budget has fully refilled, as they are back to the initial state
+func (l *InMemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	for key, tat := range l.tats {
+		if !tat.After(now) {
+			delete(l.tats, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// gcraScript is gcra() run atomically in Redis, on the Redis clock so every replica agrees on the time.
+// Times are in microseconds. It returns {allowed, remaining, reset after, retry after}.
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local used = new_tat - now
+
+if used > tolerance then
+	return {0, 0, tat - now, used - tolerance}
+end
+-- Format explicitly, Lua would print a microsecond timestamp in exponent notation
+redis.call("SET", KEYS[1], string.format("%.0f", new_tat), "PX", math.ceil(used / 1000))
+return {1, math.floor((tolerance - used) / interval), used, 0}
+`)
+
+// RedisRateLimiter shares the rate limit state of all replicas in Redis. While Redis is unreachable it
+// falls back to the given limiter, so clients stay limited per replica instead of not at all.
+type RedisRateLimiter struct {
+	redisService *RedisService
+	fallback     RateLimiter
+	failing      atomic.Bool
+}
+
+// NewRedisRateLimiter initializes Redis rate limiter
+func NewRedisRateLimiter(redisService *RedisService, fallback RateLimiter) *RedisRateLimiter {
+	return &RedisRateLimiter{redisService: redisService, fallback: fallback}
+}
+
+// Allow func
+func (l *RedisRateLimiter) Allow(ctx context.Context, policy RateLimitPolicy, key string) (*RateLimitResult, error) {
+	interval := policy.Period / time.Duration(policy.Limit)
+	values, err := gcraScript.Run(ctx, l.redisService.client, []string{"rate_limit:" + policy.Name + ":" + key},
+		interval.Microseconds(), policy.Period.Microseconds()).Int64Slice()
+	if err != nil && ctx.Err() != nil {
+		// The request is gone, which says nothing about Redis
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		// Log once per outage rather than on every request
+		if !l.failing.Swap(true) {
+			log.Printf("rate limiter: redis unavailable, limiting in memory: %v", err)
+		}
+		return l.fallback.Allow(ctx, policy, key)
+	}
+	if l.failing.Swap(false) {
+		log.Printf("rate limiter: redis available again")
+	}
+
+	return &RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      policy.Limit,
+		Remaining:  int(values[1]),
+		ResetAfter: time.Duration(values[2]) * time.Microsecond,
+		RetryAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
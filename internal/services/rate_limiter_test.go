@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGCRA(t *testing.T) {
+	// 5 requests per minute: one more every 12s, bursts of up to 5
+	policy := RateLimitPolicy{Name: "test", Limit: 5, Period: time.Minute}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name       string
+		at         time.Duration
+		allowed    bool
+		remaining  int
+		resetAfter time.Duration
+		retryAfter time.Duration
+	}{
+		{name: "first request", at: 0, allowed: true, remaining: 4, resetAfter: 12 * time.Second},
+		{name: "burst 2", at: 0, allowed: true, remaining: 3, resetAfter: 24 * time.Second},
+		{name: "burst 3", at: 0, allowed: true, remaining: 2, resetAfter: 36 * time.Second},
+		{name: "burst 4", at: 0, allowed: true, remaining: 1, resetAfter: 48 * time.Second},
+		{name: "burst 5", at: 0, allowed: true, remaining: 0, resetAfter: time.Minute},
+		{name: "over the limit", at: 0, resetAfter: time.Minute, retryAfter: 12 * time.Second},
+		{name: "still over the limit", at: 6 * time.Second, resetAfter: 54 * time.Second, retryAfter: 6 * time.Second},
+		{name: "one request refilled", at: 12 * time.Second, allowed: true, remaining: 0, resetAfter: time.Minute},
+		{name: "over the limit again", at: 12 * time.Second, resetAfter: time.Minute, retryAfter: 12 * time.Second},
+		{name: "fully refilled", at: 2 * time.Minute, allowed: true, remaining: 4, resetAfter: 12 * time.Second},
+	}
+
+	var tat time.Time
+	for _, step := range steps {
+		var result *RateLimitResult
+		tat, result = gcra(policy, tat, start.Add(step.at))
+
+		if result.Allowed != step.allowed || result.Remaining != step.remaining ||
+			result.ResetAfter != step.resetAfter || result.RetryAfter != step.retryAfter || result.Limit != policy.Limit {
+			t.Errorf("%s: got %+v, want allowed=%v remaining=%d reset=%s retry=%s",
+				step.name, result, step.allowed, step.remaining, step.resetAfter, step.retryAfter)
+		}
+	}
+}
+
+func TestGCRARejectionKeepsState(t *testing.T) {
+	policy := RateLimitPolicy{Name: "test", Limit: 1, Period: time.Second}
+	now := time.Now()
+
+	tat, _ := gcra(policy, time.Time{}, now)
+	rejectedTat, result := gcra(policy, tat, now)
+	if result.Allowed {
+		t.Fatal("second request in the period was allowed")
+	}
+	if !rejectedTat.Equal(tat) {
+		t.Errorf("a rejected request moved the tat from %s to %s", tat, rejectedTat)
+	}
+}
+
+func TestInMemoryRateLimiterKeys(t *testing.T) {
+	limiter := NewInMemoryRateLimiter()
+	ctx := context.Background()
+	login := RateLimitPolicy{Name: "login", Limit: 1, Period: time.Hour}
+	register := RateLimitPolicy{Name: "register", Limit: 1, Period: time.Hour}
+
+	allow := func(policy RateLimitPolicy, key string) bool {
+		result, err := limiter.Allow(ctx, policy, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.Allowed
+	}
+
+	if !allow(login, "ip-1") {
+		t.Fatal("first request was rejected")
+	}
+	if allow(login, "ip-1") {
+		t.Error("second request of the same client was allowed")
+	}
+	if !allow(login, "ip-2") {
+		t.Error("another client shares the budget")
+	}
+	if !allow(register, "ip-1") {
+		t.Error("another policy shares the budget")
+	}
+}
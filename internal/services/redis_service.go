@@ -4,48 +4,60 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"github.com/dedenfarhanhub/blog-service/internal/metrics"
 	"github.com/go-redis/redis/v8"
+	"math/rand"
+	"strconv"
 	"time"
 )
 
-// RedisService struct
+// RedisService struct. Every call takes the context of its caller, so Redis commands are cancelled
+// along with the request they serve.
 type RedisService struct {
 	client *redis.Client
-	ctx    context.Context
 }
 
 // NewRedisService initializes redis service
 func NewRedisService(client *redis.Client) *RedisService {
-	return &RedisService{
-		client: client,
-		ctx:    context.Background(),
-	}
+	return &RedisService{client: client}
+}
+
+// Ping checks that Redis answers
+func (r *RedisService) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
 }
 
 // SetEntity sets an entity in Redis with serialization
-func (r *RedisService) SetEntity(entityType string, id string, entity interface{}, expiration time.Duration) error {
+func (r *RedisService) SetEntity(ctx context.Context, entityType string, id string, entity interface{}, expiration time.Duration) error {
 	entityJSON, err := json.Marshal(entity)
 	if err != nil {
 		return err
 	}
-	return r.client.Set(r.ctx, entityType+":"+id, entityJSON, expiration).Err()
+	return r.client.Set(ctx, entityType+":"+id, entityJSON, expiration).Err()
 }
 
-// GetEntity retrieves any entity from Redis and deserializes it into the specified type
-func (r *RedisService) GetEntity(entityType string, id string, entity interface{}) error {
-	entityJSON, err := r.client.Get(r.ctx, entityType+":"+id).Result()
+// GetEntity retrieves any entity from Redis and deserializes it into the specified type. Every read is
+// counted as a hit, a miss or an error of its entity type.
+func (r *RedisService) GetEntity(ctx context.Context, entityType string, id string, entity interface{}) error {
+	entityJSON, err := r.client.Get(ctx, entityType+":"+id).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
+			metrics.CacheRequests.WithLabelValues(entityType, metrics.CacheMiss).Inc()
 			return nil // Entity not found
 		}
+		metrics.CacheRequests.WithLabelValues(entityType, metrics.CacheError).Inc()
+		return err
+	}
+	if err := json.Unmarshal([]byte(entityJSON), entity); err != nil {
+		metrics.CacheRequests.WithLabelValues(entityType, metrics.CacheError).Inc()
 		return err
 	}
-	return json.Unmarshal([]byte(entityJSON), entity)
+	metrics.CacheRequests.WithLabelValues(entityType, metrics.CacheHit).Inc()
+	return nil
 }
 
 // DeleteEntity removes an entity from Redis using its type and ID
-func (r *RedisService) DeleteEntity(entityType string, id string) error {
-	ctx := context.Background()  // Create a context for the Redis operations
+func (r *RedisService) DeleteEntity(ctx context.Context, entityType string, id string) error {
 	key := entityType + ":" + id // Construct the key based on entity type and ID
 
 	// Use the Redis DEL command to remove the key
@@ -56,3 +68,82 @@ func (r *RedisService) DeleteEntity(entityType string, id string) error {
 
 	return nil // Return nil if deletion was successful
 }
+
+// Exists reports whether an entity key is present in Redis
+func (r *RedisService) Exists(ctx context.Context, entityType string, id string) (bool, error) {
+	count, err := r.client.Exists(ctx, entityType+":"+id).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Increment adds one to a counter and returns the new value. The counter expires ttl after its first increment.
+func (r *RedisService) Increment(ctx context.Context, entityType string, id string, ttl time.Duration) (int64, error) {
+	key := entityType + ":" + id
+	count, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := r.client.Expire(ctx, key, ttl).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// AddExpiringMember adds a member to a set until expiresAt. Members that expired are dropped, and the set
+// itself expires with its last member.
+func (r *RedisService) AddExpiringMember(ctx context.Context, entityType string, id string, member string, expiresAt time.Time) error {
+	key := entityType + ":" + id
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+		pipe.ZAdd(ctx, key, &redis.Z{Score: float64(expiresAt.Unix()), Member: member})
+		pipe.ExpireAt(ctx, key, expiresAt)
+		return nil
+	})
+	return err
+}
+
+// ExpiringMembers returns the members of a set that have not expired yet, with the time they expire at
+func (r *RedisService) ExpiringMembers(ctx context.Context, entityType string, id string) (map[string]time.Time, error) {
+	members, err := r.client.ZRangeByScoreWithScores(ctx, entityType+":"+id, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	expiring := make(map[string]time.Time, len(members))
+	for _, member := range members {
+		if name, ok := member.Member.(string); ok {
+			expiring[name] = time.Unix(int64(member.Score), 0)
+		}
+	}
+	return expiring, nil
+}
+
+// releaseLockScript deletes the lock key only if it still holds our token
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLock tries to take a distributed lock and returns the token needed to release it
+func (r *RedisService) AcquireLock(ctx context.Context, name string, ttl time.Duration) (string, bool, error) {
+	token := strconv.FormatInt(time.Now().UnixNano(), 36) + strconv.FormatInt(rand.Int63(), 36)
+	acquired, err := r.client.SetNX(ctx, "lock:"+name, token, ttl).Result()
+	if err != nil {
+		return "", false, err
+	}
+	return token, acquired, nil
+}
+
+// ReleaseLock releases a lock previously taken with AcquireLock
+func (r *RedisService) ReleaseLock(ctx context.Context, name string, token string) error {
+	return releaseLockScript.Run(ctx, r.client, []string{"lock:" + name}, token).Err()
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"html"
+	"time"
+)
+
+// searchTitleWeight is how much more a title match counts than a content match
+const searchTitleWeight = 3.0
+
+// errEmptySearchQuery is returned for queries without any word to search for
+var errEmptySearchQuery = apperrors.Validation("empty_search_query", "search query must contain at least one word",
+	dto.FieldError{Field: "q", Message: "must contain at least one word"})
+
+// SearchService searches published posts. Backends that keep their own index are notified
+// of every post change through Index and Remove.
+type SearchService interface {
+	Search(ctx context.Context, query string, params *dto.ListQuery) ([]*dto.SearchResultResponse, error)
+	Count(ctx context.Context, query string, params *dto.ListQuery) (int64, error)
+	Index(post *entities.Post)
+	Remove(postID uint)
+}
+
+// parseSearch parses the query and applies the pagination defaults
+func parseSearch(query string, params *dto.ListQuery) ([]helpers.SearchTerm, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 10 // Default page size
+	}
+
+	terms := helpers.ParseSearchQuery(query)
+	if len(terms) == 0 {
+		return nil, errEmptySearchQuery
+	}
+	return terms, nil
+}
+
+// toSearchResult converts a matching post to a search result with highlighted title and snippet
+func toSearchResult(post *entities.Post, score float64, terms []helpers.SearchTerm) *dto.SearchResultResponse {
+	result := &dto.SearchResultResponse{
+		ID:             post.ID,
+		Title:          html.EscapeString(post.Title),
+		Slug:           post.Slug,
+		AuthorID:       post.AuthorID,
+		Score:          score,
+		TitleHighlight: helpers.Highlight(post.Title, terms),
+		Snippet:        helpers.Snippet(searchableContent(post), terms),
+	}
+	if post.PublishedAt != nil {
+		result.PublishedAt = post.PublishedAt.Format(time.RFC3339)
+	}
+	return result
+}
+
+// searchableContent returns the post content as plain text, without Markdown syntax
+func searchableContent(post *entities.Post) string {
+	if post.ContentFormat == entities.ContentFormatPlain {
+		return post.Content
+	}
+	rendered, err := helpers.RenderMarkdown(post.Content)
+	if err != nil {
+		return post.Content
+	}
+	return helpers.HTMLToText(rendered.HTML)
+}
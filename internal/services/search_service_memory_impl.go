@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// searchDocument is an indexed post with the words of its title and content in order
+type searchDocument struct {
+	post    entities.Post
+	title   []string
+	content []string
+}
+
+// InMemorySearchServiceImpl is an in-process inverted index over posts. It is meant for tests and
+// single-instance deployments: every replica keeps its own index, rebuilt from the database at start.
+type InMemorySearchServiceImpl struct {
+	mu        sync.RWMutex
+	documents map[uint]*searchDocument
+	// postings maps every word to the documents containing it
+	postings map[string]map[uint]struct{}
+}
+
+// NewInMemorySearchService initializes the in-memory search service and indexes the existing posts
+func NewInMemorySearchService(postRepo repositories.PostRepository) SearchService {
+	service := &InMemorySearchServiceImpl{
+		documents: make(map[uint]*searchDocument),
+		postings:  make(map[string]map[uint]struct{}),
+	}
+	if postRepo == nil {
+		return service
+	}
+
+	posts, err := postRepo.FindAll(context.Background())
+	if err != nil {
+		log.Printf("search index: failed to load posts: %v", err)
+		return service
+	}
+	for i := range posts {
+		service.Index(&posts[i])
+	}
+	return service
+}
+
+// Index adds or replaces a post in the index
+func (s *InMemorySearchServiceImpl) Index(post *entities.Post) {
+	document := &searchDocument{
+		post:    *post,
+		title:   wordsOf(post.Title),
+		content: wordsOf(searchableContent(post)),
+	}
+	// The index keeps its own copy without the relations
+	document.post.Author = nil
+	document.post.Comments = nil
+	document.post.Tags = nil
+	document.post.Categories = nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeLocked(post.ID)
+	s.documents[post.ID] = document
+	for _, word := range append(append([]string{}, document.title...), document.content...) {
+		if s.postings[word] == nil {
+			s.postings[word] = make(map[uint]struct{})
+		}
+		s.postings[word][post.ID] = struct{}{}
+	}
+}
+
+// Remove deletes a post from the index
+func (s *InMemorySearchServiceImpl) Remove(postID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(postID)
+}
+
+// Search returns the published posts matching every term, most relevant first
+func (s *InMemorySearchServiceImpl) Search(ctx context.Context, query string, params *dto.ListQuery) ([]*dto.SearchResultResponse, error) {
+	terms, err := parseSearch(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := s.match(terms)
+
+	offset := (params.Page - 1) * params.PageSize
+	if offset > len(hits) {
+		offset = len(hits)
+	}
+	end := offset + params.PageSize
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	results := make([]*dto.SearchResultResponse, 0, end-offset)
+	for _, hit := range hits[offset:end] {
+		results = append(results, toSearchResult(&hit.document.post, hit.score, terms))
+	}
+	return results, nil
+}
+
+// Count counts the published posts matching every term
+func (s *InMemorySearchServiceImpl) Count(ctx context.Context, query string, params *dto.ListQuery) (int64, error) {
+	terms, err := parseSearch(query, params)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(s.match(terms))), nil
+}
+
+// memorySearchHit is a matching document with its score
+type memorySearchHit struct {
+	document *searchDocument
+	score    float64
+}
+
+// match scores every visible document containing all terms. Each term contributes its
+// TF-IDF, with title occurrences weighted above content occurrences.
+func (s *InMemorySearchServiceImpl) match(terms []helpers.SearchTerm) []memorySearchHit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	total := float64(len(s.documents))
+	scores := make(map[uint]float64)
+
+	for i, term := range terms {
+		candidates := s.candidates(term)
+		idf := math.Log(1 + total/float64(len(candidates)+1))
+
+		termScores := make(map[uint]float64)
+		for id := range candidates {
+			// Every previous term must have matched too
+			if i > 0 {
+				if _, ok := scores[id]; !ok {
+					continue
+				}
+			}
+			document := s.documents[id]
+			if !isSearchable(&document.post, now) {
+				continue
+			}
+			occurrences := searchTitleWeight*float64(countMatches(document.title, term)) + float64(countMatches(document.content, term))
+			if occurrences > 0 {
+				termScores[id] = scores[id] + idf*(1+math.Log(occurrences))
+			}
+		}
+		scores = termScores
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+
+	hits := make([]memorySearchHit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, memorySearchHit{document: s.documents[id], score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].document.post.ID > hits[j].document.post.ID
+	})
+	return hits
+}
+
+// candidates returns the documents containing the first word of the term, expanding prefixes over the vocabulary
+func (s *InMemorySearchServiceImpl) candidates(term helpers.SearchTerm) map[uint]struct{} {
+	first := term.Words[0]
+	if !term.Prefix || term.IsPhrase() {
+		return s.postings[first]
+	}
+
+	candidates := make(map[uint]struct{})
+	for word, documents := range s.postings {
+		if !strings.HasPrefix(word, first) {
+			continue
+		}
+		for id := range documents {
+			candidates[id] = struct{}{}
+		}
+	}
+	return candidates
+}
+
+// removeLocked deletes a document and its postings; the caller holds the write lock
+func (s *InMemorySearchServiceImpl) removeLocked(postID uint) {
+	document, ok := s.documents[postID]
+	if !ok {
+		return
+	}
+	for _, word := range append(append([]string{}, document.title...), document.content...) {
+		delete(s.postings[word], postID)
+		if len(s.postings[word]) == 0 {
+			delete(s.postings, word)
+		}
+	}
+	delete(s.documents, postID)
+}
+
+// isSearchable reports whether the post is public, counting scheduled posts whose time has come
+// since the scheduler publishes them without going through the index
+func isSearchable(post *entities.Post, now time.Time) bool {
+	if post.Status == entities.PostStatusPublished {
+		return true
+	}
+	return post.Status == entities.PostStatusScheduled && post.PublishedAt != nil && !post.PublishedAt.After(now)
+}
+
+// countMatches counts the occurrences of the term in a sequence of words
+func countMatches(words []string, term helpers.SearchTerm) int {
+	count := 0
+	for i := 0; i+len(term.Words) <= len(words); i++ {
+		matched := true
+		for j, word := range term.Words {
+			if term.Prefix && j == len(term.Words)-1 {
+				matched = strings.HasPrefix(words[i+j], word)
+			} else {
+				matched = words[i+j] == word
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			count++
+		}
+	}
+	return count
+}
+
+// wordsOf returns the lowercase words of a text in order
+func wordsOf(text string) []string {
+	tokens := helpers.TokenizeWords(text)
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		words = append(words, token.Text)
+	}
+	return words
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+)
+
+// searchPost is a published plain text post
+func searchPost(id uint, title string, content string) *entities.Post {
+	publishedAt := time.Now().Add(-time.Hour)
+	return &entities.Post{
+		ID:            id,
+		Title:         title,
+		Content:       content,
+		ContentFormat: entities.ContentFormatPlain,
+		Status:        entities.PostStatusPublished,
+		PublishedAt:   &publishedAt,
+	}
+}
+
+// newTestSearchIndex returns an in-memory index holding the posts
+func newTestSearchIndex(posts ...*entities.Post) SearchService {
+	index := NewInMemorySearchService(nil)
+	for _, post := range posts {
+		index.Index(post)
+	}
+	return index
+}
+
+// searchIDs runs a query and returns the IDs of the results in order
+func searchIDs(t *testing.T, index SearchService, query string) []uint {
+	results, err := index.Search(context.Background(), query, &dto.ListQuery{PageSize: 100})
+	if err != nil {
+		t.Fatalf("Search(%q) failed: %v", query, err)
+	}
+	ids := make([]uint, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	return ids
+}
+
+func TestInMemorySearch(t *testing.T) {
+	index := newTestSearchIndex(
+		searchPost(1, "Go modules explained", "How versions are selected."),
+		searchPost(2, "Release notes", "This release moves the build to go modules."),
+		searchPost(3, "Modular monoliths", "Splitting a monolith into modules, in Go."),
+		searchPost(4, "Cooking pasta", "Boil the water first."),
+	)
+
+	tests := []struct {
+		query string
+		want  []uint
+	}{
+		{"pasta", []uint{4}},
+		{"PASTA", []uint{4}},
+		// Title matches rank above content matches
+		{"modules", []uint{1, 3, 2}},
+		// Every term is required
+		{"go release", []uint{2}},
+		{"go pasta", []uint{}},
+		// Phrases need the words next to each other
+		{`"go modules"`, []uint{1, 2}},
+		{`"modules go"`, []uint{}},
+		{"modul*", []uint{3, 1, 2}},
+		{"unknown", []uint{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := searchIDs(t, index, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInMemorySearchVisibility(t *testing.T) {
+	draft := searchPost(1, "Draft about gophers", "")
+	draft.Status = entities.PostStatusDraft
+	archived := searchPost(2, "Archived gophers", "")
+	archived.Status = entities.PostStatusArchived
+	due := searchPost(3, "Scheduled gophers, due", "")
+	due.Status = entities.PostStatusScheduled
+	future := searchPost(4, "Scheduled gophers, later", "")
+	future.Status = entities.PostStatusScheduled
+	later := time.Now().Add(time.Hour)
+	future.PublishedAt = &later
+
+	index := newTestSearchIndex(draft, archived, due, future, searchPost(5, "Published gophers", ""))
+	if got, want := searchIDs(t, index, "gophers"), []uint{5, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Search = %v, want only the public posts %v", got, want)
+	}
+}
+
+func TestInMemorySearchIndexAndRemove(t *testing.T) {
+	index := newTestSearchIndex(searchPost(1, "Old title", "about gophers"), searchPost(2, "Gophers", ""))
+
+	// Indexing a post again replaces its words
+	index.Index(searchPost(1, "New title", "about rabbits"))
+	if got := searchIDs(t, index, "gophers"); !reflect.DeepEqual(got, []uint{2}) {
+		t.Errorf("after re-indexing, gophers = %v, want [2]", got)
+	}
+	if got := searchIDs(t, index, "old"); len(got) != 0 {
+		t.Errorf("the old title is still found: %v", got)
+	}
+	if got := searchIDs(t, index, "rabbits"); !reflect.DeepEqual(got, []uint{1}) {
+		t.Errorf("after re-indexing, rabbits = %v, want [1]", got)
+	}
+
+	index.Remove(1)
+	index.Remove(42)
+	if got := searchIDs(t, index, "rabbits"); len(got) != 0 {
+		t.Errorf("a removed post is still found: %v", got)
+	}
+}
+
+func TestInMemorySearchPaging(t *testing.T) {
+	index := newTestSearchIndex(
+		searchPost(1, "Gophers one", ""),
+		searchPost(2, "Gophers two", ""),
+		searchPost(3, "Gophers three", ""),
+	)
+	ctx := context.Background()
+
+	count, err := index.Count(ctx, "gophers", &dto.ListQuery{})
+	if err != nil || count != 3 {
+		t.Errorf("Count = %d, %v, want 3", count, err)
+	}
+
+	// Equal scores are ordered newest ID first
+	pages := [][]uint{{3, 2}, {1}, {}}
+	for i, want := range pages {
+		results, err := index.Search(ctx, "gophers", &dto.ListQuery{Page: i + 1, PageSize: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]uint, 0, len(results))
+		for _, result := range results {
+			got = append(got, result.ID)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("page %d = %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestInMemorySearchResult(t *testing.T) {
+	index := newTestSearchIndex(searchPost(1, "Tom & Jerry chase gophers", "Gophers <b>dig</b> tunnels."))
+	results, err := index.Search(context.Background(), "gophers", &dto.ListQuery{})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Search = %v, %v", results, err)
+	}
+
+	result := results[0]
+	if result.TitleHighlight != "Tom &amp; Jerry chase <mark>gophers</mark>" {
+		t.Errorf("title highlight = %q", result.TitleHighlight)
+	}
+	if !strings.Contains(result.Snippet, "<mark>Gophers</mark>") || strings.Contains(result.Snippet, "<b>") {
+		t.Errorf("snippet = %q", result.Snippet)
+	}
+	if result.Score <= 0 {
+		t.Errorf("score = %v, want a positive score", result.Score)
+	}
+}
+
+func TestInMemorySearchEmptyQuery(t *testing.T) {
+	index := newTestSearchIndex(searchPost(1, "Gophers", ""))
+	for _, query := range []string{"", "   ", `"" * -+`} {
+		if _, err := index.Search(context.Background(), query, &dto.ListQuery{}); !errors.Is(err, errEmptySearchQuery) {
+			t.Errorf("Search(%q) = %v, want errEmptySearchQuery", query, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+)
+
+// MySQLSearchServiceImpl searches posts through the MySQL FULLTEXT indexes on title and content
+type MySQLSearchServiceImpl struct {
+	postRepo repositories.PostRepository
+}
+
+// NewMySQLSearchService initializes the MySQL full-text search service
+func NewMySQLSearchService(postRepo repositories.PostRepository) SearchService {
+	return &MySQLSearchServiceImpl{postRepo: postRepo}
+}
+
+// Search returns the published posts matching every term, most relevant first
+func (s *MySQLSearchServiceImpl) Search(ctx context.Context, query string, params *dto.ListQuery) ([]*dto.SearchResultResponse, error) {
+	terms, err := parseSearch(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := s.postRepo.Search(ctx, helpers.BooleanFullTextQuery(terms), searchTitleWeight, params)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to search posts", err)
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	posts, err := s.postRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to search posts", err)
+	}
+
+	postsByID := make(map[uint]*entities.Post, len(posts))
+	for i := range posts {
+		postsByID[posts[i].ID] = &posts[i]
+	}
+
+	// Keep the relevance order of the hits
+	results := make([]*dto.SearchResultResponse, 0, len(hits))
+	for _, hit := range hits {
+		if post, ok := postsByID[hit.ID]; ok {
+			results = append(results, toSearchResult(post, hit.Score, terms))
+		}
+	}
+	return results, nil
+}
+
+// Count counts the published posts matching every term
+func (s *MySQLSearchServiceImpl) Count(ctx context.Context, query string, params *dto.ListQuery) (int64, error) {
+	terms, err := parseSearch(query, params)
+	if err != nil {
+		return 0, err
+	}
+	count, err := s.postRepo.CountSearch(ctx, helpers.BooleanFullTextQuery(terms))
+	if err != nil {
+		return 0, apperrors.Unavailable("failed to count search results", err)
+	}
+	return count, nil
+}
+
+// Index is a no-op: MySQL maintains the FULLTEXT indexes itself
+func (s *MySQLSearchServiceImpl) Index(post *entities.Post) {}
+
+// Remove is a no-op: MySQL maintains the FULLTEXT indexes itself
+func (s *MySQLSearchServiceImpl) Remove(postID uint) {}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"log"
+)
+
+// weightedSpamDetector pairs a detector with how much its score counts in the verdict
+type weightedSpamDetector struct {
+	detector SpamDetector
+	weight   float64
+}
+
+// SpamAnalyzerImpl struct
+type SpamAnalyzerImpl struct {
+	detectors []weightedSpamDetector
+}
+
+// NewSpamAnalyzer initializes an empty spam analyzer; detectors are added with Use
+func NewSpamAnalyzer() *SpamAnalyzerImpl {
+	return &SpamAnalyzerImpl{}
+}
+
+// Use registers a detector with a weight between 0 and 1
+func (a *SpamAnalyzerImpl) Use(detector SpamDetector, weight float64) *SpamAnalyzerImpl {
+	a.detectors = append(a.detectors, weightedSpamDetector{detector: detector, weight: clampScore(weight)})
+	return a
+}
+
+// Analyze combines the detector scores with a noisy-OR, so independent weak signals add up
+// while a single strong signal is enough on its own. A failing detector is skipped.
+func (a *SpamAnalyzerImpl) Analyze(ctx context.Context, candidate *SpamCandidate) *SpamVerdict {
+	verdict := &SpamVerdict{Reasons: []string{}}
+	notSpam := 1.0
+
+	for _, weighted := range a.detectors {
+		signal, err := weighted.detector.Score(ctx, candidate)
+		if err != nil {
+			log.Printf("spam detector %s failed: %v", weighted.detector.Name(), err)
+			continue
+		}
+		if signal == nil || signal.Score <= 0 {
+			continue
+		}
+
+		notSpam *= 1 - weighted.weight*clampScore(signal.Score)
+		verdict.Reasons = append(verdict.Reasons, weighted.detector.Name()+": "+signal.Reason)
+	}
+
+	verdict.Score = clampScore(1 - notSpam)
+	return verdict
+}
+
+// Learn forwards a moderator decision to every detector that can learn from it
+func (a *SpamAnalyzerImpl) Learn(ctx context.Context, candidate *SpamCandidate, isSpam bool) {
+	for _, weighted := range a.detectors {
+		learner, ok := weighted.detector.(SpamLearner)
+		if !ok {
+			continue
+		}
+		if err := learner.Learn(ctx, candidate, isSpam); err != nil {
+			log.Printf("spam detector %s failed to learn: %v", weighted.detector.Name(), err)
+		}
+	}
+}
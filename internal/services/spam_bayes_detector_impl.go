@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"math"
+)
+
+const (
+	// bayesMinDocuments is how many spam and ham comments must be trained before the classifier votes
+	bayesMinDocuments = 10
+	// bayesMaxTokens bounds the number of tokens looked up per comment
+	bayesMaxTokens = 200
+)
+
+// NaiveBayesDetector is a locally trained naive Bayes classifier. It learns the token
+// frequencies of comments that moderators mark as spam or approve (ham).
+type NaiveBayesDetector struct {
+	spamTokenRepo repositories.SpamTokenRepository
+}
+
+// NewNaiveBayesDetector initializes a naive Bayes detector
+func NewNaiveBayesDetector(spamTokenRepo repositories.SpamTokenRepository) *NaiveBayesDetector {
+	return &NaiveBayesDetector{spamTokenRepo: spamTokenRepo}
+}
+
+// Name of the detector
+func (d *NaiveBayesDetector) Name() string {
+	return "naive_bayes"
+}
+
+// Score returns the probability that the comment is spam given its tokens
+func (d *NaiveBayesDetector) Score(ctx context.Context, candidate *SpamCandidate) (*SpamSignal, error) {
+	tokens := bayesTokens(candidate)
+	rows, err := d.spamTokenRepo.FindByTokens(ctx, append(tokens, entities.SpamDocumentsToken))
+	if err != nil {
+		return nil, err
+	}
+
+	var documents *entities.SpamToken
+	known := make([]entities.SpamToken, 0, len(rows))
+	for i := range rows {
+		if rows[i].Token == entities.SpamDocumentsToken {
+			documents = &rows[i]
+			continue
+		}
+		known = append(known, rows[i])
+	}
+
+	// Not enough training data yet to give a meaningful answer
+	if documents == nil || documents.SpamCount < bayesMinDocuments || documents.HamCount < bayesMinDocuments || len(known) == 0 {
+		return &SpamSignal{}, nil
+	}
+
+	spamDocuments := float64(documents.SpamCount)
+	hamDocuments := float64(documents.HamCount)
+
+	// Work in log space with Laplace smoothing to avoid underflow and zero probabilities
+	logSpam := math.Log(spamDocuments / (spamDocuments + hamDocuments))
+	logHam := math.Log(hamDocuments / (spamDocuments + hamDocuments))
+	for _, token := range known {
+		logSpam += math.Log((float64(token.SpamCount) + 1) / (spamDocuments + 2))
+		logHam += math.Log((float64(token.HamCount) + 1) / (hamDocuments + 2))
+	}
+
+	probability := 1 / (1 + math.Exp(logHam-logSpam))
+	if probability < 0.5 {
+		return &SpamSignal{}, nil
+	}
+	return &SpamSignal{Score: probability, Reason: fmt.Sprintf("classified as spam with probability %.2f", probability)}, nil
+}
+
+// Learn records the tokens of a moderated comment as spam or ham
+func (d *NaiveBayesDetector) Learn(ctx context.Context, candidate *SpamCandidate, isSpam bool) error {
+	return d.spamTokenRepo.Increment(ctx, append(bayesTokens(candidate), entities.SpamDocumentsToken), isSpam)
+}
+
+// bayesTokens returns the tokens of the author name and content, the same for scoring and learning
+func bayesTokens(candidate *SpamCandidate) []string {
+	tokens := tokenizeForSpam(candidate.AuthorName + " " + candidate.Content)
+	if len(tokens) > bayesMaxTokens {
+		tokens = tokens[:bayesMaxTokens]
+	}
+	return tokens
+}
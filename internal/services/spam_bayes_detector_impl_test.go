@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dedenfarhanhub/blog-service/internal/entities"
+)
+
+// memorySpamTokenRepository keeps token counts in memory
+type memorySpamTokenRepository struct {
+	tokens map[string]*entities.SpamToken
+}
+
+func (r *memorySpamTokenRepository) FindByTokens(ctx context.Context, tokens []string) ([]entities.SpamToken, error) {
+	var found []entities.SpamToken
+	for _, token := range tokens {
+		if row, ok := r.tokens[token]; ok {
+			found = append(found, *row)
+		}
+	}
+	return found, nil
+}
+
+func (r *memorySpamTokenRepository) Increment(ctx context.Context, tokens []string, isSpam bool) error {
+	for _, token := range tokens {
+		row, ok := r.tokens[token]
+		if !ok {
+			row = &entities.SpamToken{Token: token}
+			r.tokens[token] = row
+		}
+		if isSpam {
+			row.SpamCount++
+		} else {
+			row.HamCount++
+		}
+	}
+	return nil
+}
+
+func TestNaiveBayesLearnsAuthorNames(t *testing.T) {
+	repo := &memorySpamTokenRepository{tokens: map[string]*entities.SpamToken{}}
+	detector := NewNaiveBayesDetector(repo)
+	ctx := context.Background()
+
+	// Spam and ham share their content and only differ by author name
+	for i := 0; i < bayesMinDocuments; i++ {
+		if err := detector.Learn(ctx, &SpamCandidate{AuthorName: "Cheap Pills", Content: "nice post"}, true); err != nil {
+			t.Fatal(err)
+		}
+		if err := detector.Learn(ctx, &SpamCandidate{AuthorName: "Alice", Content: "nice post"}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if row := repo.tokens["pills"]; row == nil || row.SpamCount != bayesMinDocuments {
+		t.Fatalf("author name tokens were not learned: %+v", row)
+	}
+
+	spam, err := detector.Score(ctx, &SpamCandidate{AuthorName: "Cheap Pills", Content: "nice post"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spam.Score < 0.9 {
+		t.Errorf("spam author scored %.2f, want at least 0.9", spam.Score)
+	}
+
+	ham, err := detector.Score(ctx, &SpamCandidate{AuthorName: "Alice", Content: "nice post"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ham.Score != 0 {
+		t.Errorf("ham author scored %.2f, want 0", ham.Score)
+	}
+}
+
+func TestNaiveBayesWaitsForTrainingData(t *testing.T) {
+	repo := &memorySpamTokenRepository{tokens: map[string]*entities.SpamToken{}}
+	detector := NewNaiveBayesDetector(repo)
+	ctx := context.Background()
+
+	candidate := &SpamCandidate{AuthorName: "Cheap Pills", Content: "buy now"}
+	for i := 0; i < bayesMinDocuments-1; i++ {
+		_ = detector.Learn(ctx, candidate, true)
+		_ = detector.Learn(ctx, &SpamCandidate{AuthorName: "Alice", Content: "thanks"}, false)
+	}
+
+	signal, err := detector.Score(ctx, candidate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signal.Score != 0 {
+		t.Errorf("scored %.2f before %d documents of each kind were trained", signal.Score, bayesMinDocuments)
+	}
+}
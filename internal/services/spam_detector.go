@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// SpamCandidate is the comment submission handed to spam detectors before it is saved.
+type SpamCandidate struct {
+	PostID     uint
+	UserID     uint
+	AuthorName string
+	Content    string
+	Honeypot   string
+	ClientIP   string
+}
+
+// SpamSignal is the outcome of a single detector: a score between 0 (clean) and 1 (certain spam)
+// and a human readable reason when the score is above zero.
+type SpamSignal struct {
+	Score  float64
+	Reason string
+}
+
+// SpamVerdict is the combined outcome of every detector.
+type SpamVerdict struct {
+	Score   float64
+	Reasons []string
+}
+
+// SpamDetector scores a comment submission
+type SpamDetector interface {
+	Name() string
+	Score(ctx context.Context, candidate *SpamCandidate) (*SpamSignal, error)
+}
+
+// SpamLearner is implemented by detectors that learn from moderator decisions. The candidate is
+// rebuilt from the moderated comment, so learners see the same fields they score.
+type SpamLearner interface {
+	Learn(ctx context.Context, candidate *SpamCandidate, isSpam bool) error
+}
+
+// SpamAnalyzer runs every configured detector and forwards moderator decisions to the learners
+type SpamAnalyzer interface {
+	Analyze(ctx context.Context, candidate *SpamCandidate) *SpamVerdict
+	Learn(ctx context.Context, candidate *SpamCandidate, isSpam bool)
+}
+
+// tokenizeForSpam lower-cases text and returns its unique words of 2 to 40 letters or digits
+func tokenizeForSpam(text string) []string {
+	seen := make(map[string]struct{})
+	tokens := make([]string, 0)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		length := len([]rune(word))
+		if length < 2 || length > 40 {
+			continue
+		}
+		if _, ok := seen[word]; ok {
+			continue
+		}
+		seen[word] = struct{}{}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// clampScore keeps a score within [0, 1]
+func clampScore(score float64) float64 {
+	return math.Max(0, math.Min(1, score))
+}
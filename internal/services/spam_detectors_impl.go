@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/config"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// NewDefaultSpamAnalyzer builds the analyzer with every built-in detector, configured from the environment
+func NewDefaultSpamAnalyzer(spamTokenRepo repositories.SpamTokenRepository, redisService *RedisService) SpamAnalyzer {
+	cfg := config.LoadConfig()
+
+	return NewSpamAnalyzer().
+		Use(&HoneypotDetector{}, 1).
+		Use(NewBlockedWordsDetector(cfg.SpamBlockedWords), 0.95).
+		Use(NewNaiveBayesDetector(spamTokenRepo), 0.9).
+		Use(NewDuplicateContentDetector(redisService, 24*time.Hour), 0.8).
+		Use(NewSubmissionRateDetector(redisService, cfg.SpamMinSubmitInterval), 0.7).
+		Use(NewLinkDensityDetector(3), 0.6)
+}
+
+// linkPattern matches URLs and BBCode links
+var linkPattern = regexp.MustCompile(`(?i)https?://\S+|\bwww\.\S+|\[url[=\]]`)
+
+// LinkDensityDetector flags comments made mostly of links
+type LinkDensityDetector struct {
+	maxLinks int
+}
+
+// NewLinkDensityDetector initializes a link density detector; maxLinks links or more score 1
+func NewLinkDensityDetector(maxLinks int) *LinkDensityDetector {
+	if maxLinks < 1 {
+		maxLinks = 1
+	}
+	return &LinkDensityDetector{maxLinks: maxLinks}
+}
+
+// Name of the detector
+func (d *LinkDensityDetector) Name() string {
+	return "link_density"
+}
+
+// Score compares the number of links with the number of words
+func (d *LinkDensityDetector) Score(ctx context.Context, candidate *SpamCandidate) (*SpamSignal, error) {
+	links := len(linkPattern.FindAllStringIndex(candidate.Content, -1))
+	if links == 0 {
+		return &SpamSignal{}, nil
+	}
+
+	words := len(strings.Fields(candidate.Content))
+	if words == 0 {
+		words = 1
+	}
+	density := float64(links) / float64(words)
+
+	// A single link in a normal sentence is fine
+	if links == 1 && density < 0.2 {
+		return &SpamSignal{}, nil
+	}
+
+	score := clampScore(float64(links) / float64(d.maxLinks))
+	if densityScore := clampScore(density * 2); densityScore > score {
+		score = densityScore
+	}
+	return &SpamSignal{Score: score, Reason: fmt.Sprintf("%d link(s) in %d word(s)", links, words)}, nil
+}
+
+// BlockedWordsDetector flags comments containing a configured word or phrase
+type BlockedWordsDetector struct {
+	words   map[string]struct{}
+	phrases []string
+}
+
+// NewBlockedWordsDetector initializes a blocked words detector; entries with spaces are matched as phrases
+func NewBlockedWordsDetector(blocked []string) *BlockedWordsDetector {
+	detector := &BlockedWordsDetector{words: make(map[string]struct{})}
+	for _, entry := range blocked {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.ContainsAny(entry, " \t") {
+			detector.phrases = append(detector.phrases, entry)
+		} else {
+			detector.words[entry] = struct{}{}
+		}
+	}
+	return detector
+}
+
+// Name of the detector
+func (d *BlockedWordsDetector) Name() string {
+	return "blocked_words"
+}
+
+// Score looks for blocked words in the author name and the content
+func (d *BlockedWordsDetector) Score(ctx context.Context, candidate *SpamCandidate) (*SpamSignal, error) {
+	text := candidate.AuthorName + " " + candidate.Content
+
+	var matches []string
+	for _, token := range tokenizeForSpam(text) {
+		if _, ok := d.words[token]; ok {
+			matches = append(matches, token)
+		}
+	}
+	lowered := strings.ToLower(text)
+	for _, phrase := range d.phrases {
+		if strings.Contains(lowered, phrase) {
+			matches = append(matches, phrase)
+		}
+	}
+
+	if len(matches) == 0 {
+		return &SpamSignal{}, nil
+	}
+	return &SpamSignal{Score: 1, Reason: "contains blocked words: " + strings.Join(matches, ", ")}, nil
+}
+
+// HoneypotDetector flags submissions that filled in the hidden honeypot field
+type HoneypotDetector struct{}
+
+// Name of the detector
+func (d *HoneypotDetector) Name() string {
+	return "honeypot"
+}
+
+// Score is 1 when the honeypot field is not empty
+func (d *HoneypotDetector) Score(ctx context.Context, candidate *SpamCandidate) (*SpamSignal, error) {
+	if strings.TrimSpace(candidate.Honeypot) == "" {
+		return &SpamSignal{}, nil
+	}
+	return &SpamSignal{Score: 1, Reason: "honeypot field was filled in"}, nil
+}
+
+// SubmissionRateDetector flags clients that submit comments faster than a human could
+type SubmissionRateDetector struct {
+	redisService *RedisService
+	minInterval  time.Duration
+}
+
+// NewSubmissionRateDetector initializes a submission rate detector
+func NewSubmissionRateDetector(redisService *RedisService, minInterval time.Duration) *SubmissionRateDetector {
+	return &SubmissionRateDetector{redisService: redisService, minInterval: minInterval}
+}
+
+// Name of the detector
+func (d *SubmissionRateDetector) Name() string {
+	return "submission_rate"
+}
+
+// Score checks when the same client last submitted a comment, then records this submission
+func (d *SubmissionRateDetector) Score(ctx context.Context, candidate *SpamCandidate) (*SpamSignal, error) {
+	client := candidate.ClientIP
+	if candidate.UserID != 0 {
+		client = "user-" + fmt.Sprint(candidate.UserID)
+	}
+	if client == "" || d.minInterval <= 0 {
+		return &SpamSignal{}, nil
+	}
+
+	var lastSubmission int64
+	if err := d.redisService.GetEntity(ctx, "comment_rate", client, &lastSubmission); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := d.redisService.SetEntity(ctx, "comment_rate", client, now.UnixMilli(), d.minInterval); err != nil {
+		return nil, err
+	}
+
+	if lastSubmission == 0 {
+		return &SpamSignal{}, nil
+	}
+	elapsed := now.Sub(time.UnixMilli(lastSubmission))
+	if elapsed >= d.minInterval {
+		return &SpamSignal{}, nil
+	}
+	return &SpamSignal{
+		Score:  1 - float64(elapsed)/float64(d.minInterval),
+		Reason: fmt.Sprintf("submitted %s after the previous comment", elapsed.Round(time.Millisecond)),
+	}, nil
+}
+
+// DuplicateContentDetector flags content that was already submitted recently
+type DuplicateContentDetector struct {
+	redisService *RedisService
+	window       time.Duration
+}
+
+// NewDuplicateContentDetector initializes a duplicate content detector remembering content for the given window
+func NewDuplicateContentDetector(redisService *RedisService, window time.Duration) *DuplicateContentDetector {
+	return &DuplicateContentDetector{redisService: redisService, window: window}
+}
+
+// Name of the detector
+func (d *DuplicateContentDetector) Name() string {
+	return "duplicate_content"
+}
+
+// Score checks whether the normalized content was seen within the window, then remembers it.
+// Very short comments ("thanks!") are legitimately repeated and are ignored.
+func (d *DuplicateContentDetector) Score(ctx context.Context, candidate *SpamCandidate) (*SpamSignal, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(candidate.Content)), " ")
+	if len(normalized) < 20 {
+		return &SpamSignal{}, nil
+	}
+
+	hash := helpers.HashToken(normalized)
+	seen, err := d.redisService.Exists(ctx, "comment_hash", hash)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.redisService.SetEntity(ctx, "comment_hash", hash, candidate.PostID, d.window); err != nil {
+		return nil, err
+	}
+
+	if !seen {
+		return &SpamSignal{}, nil
+	}
+	return &SpamSignal{Score: 1, Reason: "the same content was submitted recently"}, nil
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+)
+
+// TagService interface
+type TagService interface {
+	GetAll(ctx context.Context, params *dto.ListQuery) ([]*dto.TagResponse, error)
+	Count(ctx context.Context, params *dto.ListQuery) (int64, error)
+	CollectGarbage(ctx context.Context) (int64, error)
+}
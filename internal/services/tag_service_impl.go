@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
+	"github.com/dedenfarhanhub/blog-service/internal/dto"
+	"github.com/dedenfarhanhub/blog-service/internal/repositories"
+	"time"
+)
+
+const (
+	tagCollectorLock    = "tag_collector"
+	tagCollectorLockTTL = time.Minute
+	// tagGracePeriod keeps freshly created tags alive until the post they were created for is saved
+	tagGracePeriod = time.Hour
+)
+
+// TagServiceImpl struct
+type TagServiceImpl struct {
+	tagRepo      repositories.TagRepository
+	redisService *RedisService
+}
+
+// NewTagService initializes tag service
+func NewTagService(tagRepo repositories.TagRepository, redisService *RedisService) TagService {
+	return &TagServiceImpl{
+		tagRepo:      tagRepo,
+		redisService: redisService,
+	}
+}
+
+// GetAll lists the tags of published posts with their post counts, most used first
+func (s *TagServiceImpl) GetAll(ctx context.Context, params *dto.ListQuery) ([]*dto.TagResponse, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = 10 // Default page size
+	}
+
+	tags, err := s.tagRepo.FindAllWithPostCounts(ctx, params)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to retrieve tags", err)
+	}
+
+	tagResponses := make([]*dto.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		tagResponses = append(tagResponses, tag.ToTagResponse())
+	}
+
+	return tagResponses, nil
+}
+
+// Count counts the tags of published posts
+func (s *TagServiceImpl) Count(ctx context.Context, params *dto.ListQuery) (int64, error) {
+	count, err := s.tagRepo.CountWithPosts(ctx, params)
+	if err != nil {
+		return 0, apperrors.Unavailable("failed to count tags", err)
+	}
+	return count, nil
+}
+
+// CollectGarbage deletes the tags no post uses anymore. A Redis lock keeps replicas from doing the same work.
+func (s *TagServiceImpl) CollectGarbage(ctx context.Context) (int64, error) {
+	token, acquired, err := s.redisService.AcquireLock(ctx, tagCollectorLock, tagCollectorLockTTL)
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		return 0, nil
+	}
+	defer func() {
+		_ = s.redisService.ReleaseLock(ctx, tagCollectorLock, token)
+	}()
+
+	return s.tagRepo.DeleteUnused(ctx, time.Now().Add(-tagGracePeriod))
+}
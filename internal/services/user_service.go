@@ -1,14 +1,30 @@
 package services
 
 import (
+	"context"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 	"github.com/dedenfarhanhub/blog-service/internal/entities"
+	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+)
+
+// Errors of UserService
+var (
+	ErrUserNotFound      = apperrors.NotFound("user_not_found", "user not found")
+	ErrEmailTaken        = apperrors.Conflict("email_taken", "email already in use")
+	ErrCannotManageUsers = apperrors.Forbidden("permission_denied", "you do not have permission to manage users")
 )
 
 // UserService interface
 type UserService interface {
-	Register(userRequest *dto.UserRequest) (*dto.UserResponse, error)
+	Register(ctx context.Context, userRequest *dto.UserRequest) (*dto.UserResponse, error)
 	HashPassword(password string) (string, error)
-	Login(userLoginRequest *dto.UserLoginRequest) (*dto.UserLoginResponse, error)
-	FindAuthorByID(authorID uint) (*entities.User, error)
+	Login(ctx context.Context, userLoginRequest *dto.UserLoginRequest) (*dto.UserLoginResponse, error)
+	FindAuthorByID(ctx context.Context, authorID uint) (*entities.User, error)
+	UpdateRole(ctx context.Context, userID uint, role string, actor *dto.Actor) (*dto.UserRoleResponse, error)
+	UnlockLogin(ctx context.Context, userID uint, actor *dto.Actor) error
+	GetProfile(ctx context.Context, userID uint) (*dto.ProfileResponse, error)
+	UpdateProfile(ctx context.Context, userID uint, profileRequest *dto.UpdateProfileRequest) (*dto.ProfileResponse, error)
+	ChangePassword(ctx context.Context, userID uint, changePasswordRequest *dto.ChangePasswordRequest) (*dto.TokenResponse, error)
+	DeleteAccount(ctx context.Context, claims *helpers.Claims, password string) error
 }
@@ -1,33 +1,54 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"github.com/dedenfarhanhub/blog-service/config"
+	"fmt"
+	"github.com/dedenfarhanhub/blog-service/internal/apperrors"
 	"github.com/dedenfarhanhub/blog-service/internal/dto"
 	"github.com/dedenfarhanhub/blog-service/internal/entities"
 	"github.com/dedenfarhanhub/blog-service/internal/helpers"
+	"github.com/dedenfarhanhub/blog-service/internal/metrics"
 	"github.com/dedenfarhanhub/blog-service/internal/repositories"
 	"golang.org/x/crypto/bcrypt"
+	"log"
+	"net/url"
 	"regexp"
+	"strings"
 	"time"
 )
 
 // UserServiceImpl struct
 type UserServiceImpl struct {
-	userRepo     repositories.UserRepository
-	redisService *RedisService
+	userRepo       repositories.UserRepository
+	postRepo       repositories.PostRepository
+	searchService  SearchService
+	authService    AuthService
+	auditService   AuditService
+	accountService AccountService
+	loginGuard     LoginGuard
+	redisService   *RedisService
 }
 
+// dummyPasswordHash is compared against on logins with an unknown email, so they take as long as a wrong password
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
 // NewUserService initialize user service
-func NewUserService(userRepo repositories.UserRepository, redisService *RedisService) UserService {
+func NewUserService(userRepo repositories.UserRepository, postRepo repositories.PostRepository, searchService SearchService, authService AuthService, auditService AuditService, accountService AccountService, loginGuard LoginGuard, redisService *RedisService) UserService {
 	return &UserServiceImpl{
-		userRepo:     userRepo,
-		redisService: redisService,
+		userRepo:       userRepo,
+		postRepo:       postRepo,
+		searchService:  searchService,
+		authService:    authService,
+		auditService:   auditService,
+		accountService: accountService,
+		loginGuard:     loginGuard,
+		redisService:   redisService,
 	}
 }
 
 // Register a new user
-func (s *UserServiceImpl) Register(userRequest *dto.UserRequest) (*dto.UserResponse, error) {
+func (s *UserServiceImpl) Register(ctx context.Context, userRequest *dto.UserRequest) (*dto.UserResponse, error) {
 	// Validate email and password
 	if err := validateEmail(userRequest.Email); err != nil {
 		return nil, err
@@ -36,16 +57,16 @@ func (s *UserServiceImpl) Register(userRequest *dto.UserRequest) (*dto.UserRespo
 	// Hash the password
 	hashedPassword, err := s.HashPassword(userRequest.Password)
 	if err != nil {
-		return nil, errors.New("password hashing failed")
+		return nil, fmt.Errorf("password hashing failed: %w", err)
 	}
 
 	// Check if user exists in Redis
-	existingUser, err := s.findUserByEmail(userRequest.Email)
+	existingUser, err := s.findUserByEmail(ctx, userRequest.Email)
 	if err != nil {
-		return nil, errors.New("failed to check existing email")
+		return nil, err
 	}
 	if existingUser != nil {
-		return nil, errors.New("email already in use")
+		return nil, ErrEmailTaken
 	}
 
 	// Create a new user entity
@@ -53,26 +74,37 @@ func (s *UserServiceImpl) Register(userRequest *dto.UserRequest) (*dto.UserRespo
 		Name:         userRequest.Name,
 		Email:        userRequest.Email,
 		PasswordHash: hashedPassword,
+		Role:         string(entities.DefaultRole),
 	}
 
 	// Save the new user to the database
-	if err := s.userRepo.Create(userEntity); err != nil {
-		return nil, errors.New("failed to create user")
+	if err := s.userRepo.Create(ctx, userEntity); err != nil {
+		return nil, dependencyError("failed to create user", err)
 	}
+	metrics.UsersRegistered.Inc()
 
 	// Store the new user in Redis
-	if err := s.redisService.SetEntity("user", userEntity.Email, userEntity, 24*time.Hour); err != nil {
-		return nil, errors.New("failed to store user in Redis")
+	if err := s.redisService.SetEntity(ctx, "user", userEntity.Email, userEntity, 24*time.Hour); err != nil {
+		return nil, apperrors.Unavailable("failed to store user in Redis", err)
 	}
 
-	// Generate JWT token
-	cfg := config.LoadConfig()
-	token, err := helpers.GenerateToken(userEntity.Email, userEntity.ID, []byte(cfg.JWTSecret))
+	// Ask the user to verify their email; a mail failure does not fail the registration, as the
+	// verification can be requested again
+	background, cancel := detach(ctx)
+	go func() {
+		defer cancel()
+		if err := s.accountService.SendEmailVerification(background, userEntity); err != nil {
+			log.Printf("email verification for user %d: %v", userEntity.ID, err)
+		}
+	}()
+
+	// Generate access and refresh tokens
+	tokens, err := s.authService.IssueTokens(ctx, userEntity)
 	if err != nil {
-		return nil, errors.New("could not generate token")
+		return nil, err
 	}
 
-	return userEntity.ToUserResponse(token), nil
+	return userEntity.ToUserResponse(tokens), nil
 }
 
 // HashPassword hashes the user's password
@@ -82,80 +114,349 @@ func (s *UserServiceImpl) HashPassword(password string) (string, error) {
 }
 
 // Login authenticates the user and returns the user entity
-func (s *UserServiceImpl) Login(userLoginRequest *dto.UserLoginRequest) (*dto.UserLoginResponse, error) {
+func (s *UserServiceImpl) Login(ctx context.Context, userLoginRequest *dto.UserLoginRequest) (*dto.UserLoginResponse, error) {
 	// Validate email
 	if err := validateEmail(userLoginRequest.Email); err != nil {
 		return nil, err
 	}
 
+	// Refuse attempts while the account or the IP is locked out or backing off
+	if err := s.loginGuard.Check(ctx, userLoginRequest.Email, userLoginRequest.ClientIP); err != nil {
+		var throttled *LoginThrottledError
+		if errors.As(err, &throttled) {
+			metrics.Logins.WithLabelValues(metrics.LoginThrottled).Inc()
+			return nil, err
+		}
+		return nil, apperrors.Unavailable("failed to check login attempts", err)
+	}
+
 	// Check Redis for existing user
-	existingUser, err := s.findUserByEmail(userLoginRequest.Email)
+	existingUser, err := s.findUserByEmail(ctx, userLoginRequest.Email)
 	if err != nil {
-		return nil, errors.New("failed to check existing email")
+		return nil, err
 	}
-	if existingUser == nil {
-		return nil, errors.New("invalid credentials")
+
+	// Validate the password. Unknown emails are checked against a dummy hash and counted like wrong
+	// passwords, so the response does not reveal whether an email is registered.
+	passwordHash, userID := dummyPasswordHash, uint(0)
+	if existingUser != nil {
+		passwordHash, userID = []byte(existingUser.PasswordHash), existingUser.ID
+	}
+	if bcrypt.CompareHashAndPassword(passwordHash, []byte(userLoginRequest.Password)) != nil || existingUser == nil {
+		if err := s.loginGuard.RecordFailure(ctx, userLoginRequest.Email, userID, userLoginRequest.ClientIP); err != nil {
+			return nil, apperrors.Unavailable("failed to record login attempt", err)
+		}
+		metrics.Logins.WithLabelValues(metrics.LoginFailure).Inc()
+		return nil, apperrors.Unauthorized("invalid_credentials", "invalid credentials")
 	}
 
-	// Validate the password
-	if bcrypt.CompareHashAndPassword([]byte(existingUser.PasswordHash), []byte(userLoginRequest.Password)) != nil {
-		return nil, errors.New("invalid credentials")
+	if err := s.loginGuard.RecordSuccess(ctx, userLoginRequest.Email); err != nil {
+		return nil, apperrors.Unavailable("failed to record login attempt", err)
 	}
 
-	// Generate JWT token
-	cfg := config.LoadConfig()
-	token, err := helpers.GenerateToken(existingUser.Email, existingUser.ID, []byte(cfg.JWTSecret))
+	// Generate access and refresh tokens
+	tokens, err := s.authService.IssueTokens(ctx, existingUser)
 	if err != nil {
-		return nil, errors.New("could not generate token")
+		return nil, err
 	}
+	metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
 
-	return existingUser.ToUserLoginResponse(token), nil
+	return existingUser.ToUserLoginResponse(tokens), nil
 }
 
-// FindAuthorByID fetches the author (user) by their ID
-func (s *UserServiceImpl) FindAuthorByID(authorID uint) (*entities.User, error) {
+// FindAuthorByID fetches the author (user) by their ID, failing with ErrUserNotFound when there is none
+func (s *UserServiceImpl) FindAuthorByID(ctx context.Context, authorID uint) (*entities.User, error) {
 	author := &entities.User{}
 	authorIDStr, _ := helpers.ConvertToString(authorID)
 	// Attempt to get the user (author) from Redis first
-	err := s.redisService.GetEntity("user", authorIDStr, author)
+	err := s.redisService.GetEntity(ctx, "user", authorIDStr, author)
 	if err == nil && author.ID != 0 {
 		return author, nil
 	}
 
 	// If not found in Redis, check the database
-	author, err = s.userRepo.FindByID(authorID)
+	author, err = s.userRepo.FindByID(ctx, authorID)
 	if err != nil {
-		return nil, errors.New("author not found")
+		return nil, apperrors.Unavailable("failed to find author", err)
+	}
+	if author == nil {
+		return nil, ErrUserNotFound
 	}
 
 	// Optionally cache the author in Redis for future use
-	_ = s.redisService.SetEntity("user", authorIDStr, author, 24*time.Hour)
+	_ = s.redisService.SetEntity(ctx, "user", authorIDStr, author, 24*time.Hour)
 
 	return author, nil
 }
 
+// UpdateRole changes the role of a user. The user's access tokens, which carry the old role, are
+// revoked, so the new role applies from their next token refresh.
+func (s *UserServiceImpl) UpdateRole(ctx context.Context, userID uint, role string, actor *dto.Actor) (*dto.UserRoleResponse, error) {
+	if !entities.Role(actor.Role).HasPermission(entities.PermissionUserManage) {
+		return nil, ErrCannotManageUsers
+	}
+	if !entities.Role(role).IsValid() {
+		return nil, apperrors.Validation("invalid_role", "invalid role",
+			dto.FieldError{Field: "role", Message: "must be one of admin, editor, author, reader"})
+	}
+	if userID == actor.ID {
+		return nil, apperrors.Forbidden("own_role_change", "you cannot change your own role")
+	}
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousRole := user.GetRole()
+	if err := s.userRepo.UpdateRole(ctx, user.ID, role); err != nil {
+		return nil, apperrors.Unavailable("failed to update role", err)
+	}
+	user.Role = role
+
+	// The role is saved: revoke the tokens carrying the old one even if the client goes away
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
+	invalidateUserCache(ctx, s.redisService, user)
+	s.auditService.Record(ctx, actor, AuditActionUserRole, "user", user.ID, string(previousRole)+" -> "+role)
+	if err := s.authService.RevokeAccessTokens(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user.ToUserRoleResponse(), nil
+}
+
+// UnlockLogin lifts the login lockout of a user before it runs out
+func (s *UserServiceImpl) UnlockLogin(ctx context.Context, userID uint, actor *dto.Actor) error {
+	if !entities.Role(actor.Role).HasPermission(entities.PermissionUserManage) {
+		return ErrCannotManageUsers
+	}
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.loginGuard.Unlock(ctx, user.Email, user.ID, actor); err != nil {
+		return apperrors.Unavailable("failed to unlock user", err)
+	}
+	return nil
+}
+
+// GetProfile returns the account of a user
+func (s *UserServiceImpl) GetProfile(ctx context.Context, userID uint) (*dto.ProfileResponse, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.ToProfileResponse(), nil
+}
+
+// UpdateProfile changes the fields sent in the request. A new email is only requested here; it
+// replaces the current one once confirmed through the link mailed to it.
+func (s *UserServiceImpl) UpdateProfile(ctx context.Context, userID uint, profileRequest *dto.UpdateProfileRequest) (*dto.ProfileResponse, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if profileRequest.Name != nil {
+		name := strings.TrimSpace(*profileRequest.Name)
+		if name == "" {
+			return nil, apperrors.Validation("invalid_profile", "name cannot be empty",
+				dto.FieldError{Field: "name", Message: "cannot be empty"})
+		}
+		user.Name = name
+	}
+	if profileRequest.Bio != nil {
+		user.Bio = strings.TrimSpace(*profileRequest.Bio)
+	}
+	if profileRequest.AvatarURL != nil {
+		if user.AvatarURL, err = validateProfileURL("avatar_url", *profileRequest.AvatarURL); err != nil {
+			return nil, err
+		}
+	}
+	if profileRequest.Website != nil {
+		if user.Website, err = validateProfileURL("website", *profileRequest.Website); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check the email change before saving anything, so an invalid request changes nothing
+	newEmail := ""
+	if profileRequest.Email != nil && !strings.EqualFold(*profileRequest.Email, user.Email) {
+		newEmail = strings.TrimSpace(*profileRequest.Email)
+		if err := validateEmail(newEmail); err != nil {
+			return nil, err
+		}
+		if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(profileRequest.CurrentPassword)) != nil {
+			return nil, errIncorrectPassword("current_password", "current password is incorrect")
+		}
+		existingUser, err := s.findUserByEmail(ctx, newEmail)
+		if err != nil {
+			return nil, err
+		}
+		if existingUser != nil {
+			return nil, ErrEmailTaken
+		}
+	}
+
+	if err := s.userRepo.UpdateProfile(ctx, user); err != nil {
+		return nil, apperrors.Unavailable("failed to update profile", err)
+	}
+	invalidateUserCache(ctx, s.redisService, user)
+
+	switch {
+	case newEmail != "":
+		if err := s.accountService.RequestEmailChange(ctx, user, newEmail); err != nil {
+			return nil, err
+		}
+		user.PendingEmail = &newEmail
+	case profileRequest.Email != nil && user.PendingEmail != nil:
+		// Asking for the current address again cancels a pending change
+		if err := s.userRepo.SetPendingEmail(ctx, user.ID, nil); err != nil {
+			return nil, apperrors.Unavailable("failed to cancel email change", err)
+		}
+		user.PendingEmail = nil
+	}
+
+	return user.ToProfileResponse(), nil
+}
+
+// ChangePassword replaces the password of a user who knows the current one. All sessions are
+// signed out, and a new one is started for the caller.
+func (s *UserServiceImpl) ChangePassword(ctx context.Context, userID uint, changePasswordRequest *dto.ChangePasswordRequest) (*dto.TokenResponse, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(changePasswordRequest.CurrentPassword)) != nil {
+		return nil, errIncorrectPassword("current_password", "current password is incorrect")
+	}
+
+	hashedPassword, err := s.HashPassword(changePasswordRequest.NewPassword)
+	if err != nil {
+		return nil, fmt.Errorf("password hashing failed: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return nil, apperrors.Unavailable("failed to update password", err)
+	}
+	invalidateUserCache(ctx, s.redisService, user)
+
+	if err := s.authService.RevokeAllSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return s.authService.IssueTokens(ctx, user)
+}
+
+// DeleteAccount deletes the account of the signed-in user after checking their password, together
+// with their posts. Their comments are kept without the account.
+func (s *UserServiceImpl) DeleteAccount(ctx context.Context, claims *helpers.Claims, password string) error {
+	user, err := s.findUser(ctx, claims.ID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return errIncorrectPassword("password", "password is incorrect")
+	}
+
+	// The posts go with the account through the foreign key, so find them first to clear their cache
+	postSlugs, err := s.postRepo.FindSlugsByAuthor(ctx, user.ID)
+	if err != nil {
+		return apperrors.Unavailable("failed to find posts of account", err)
+	}
+
+	// Sign out everywhere first, so the access token cannot be used on a deleted account
+	if err := s.authService.Logout(ctx, claims, &dto.LogoutRequest{AllSessions: true}); err != nil {
+		return err
+	}
+	if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+		return apperrors.Unavailable("failed to delete account", err)
+	}
+	invalidateUserCache(ctx, s.redisService, user)
+	s.evictPosts(ctx, postSlugs)
+
+	actor := &dto.Actor{ID: user.ID, Role: string(user.GetRole())}
+	s.auditService.Record(ctx, actor, AuditActionUserDelete, "user", user.ID, user.Email)
+	return nil
+}
+
+// evictPosts clears the posts of a deleted account from the search index and the cache. The account is
+// gone by then, so failures are only logged: posts left in the cache are dropped when read without an author.
+func (s *UserServiceImpl) evictPosts(ctx context.Context, postSlugs map[uint][]string) {
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
+	for postID, slugs := range postSlugs {
+		if err := evictPost(ctx, s.redisService, s.searchService, postID, slugs); err != nil {
+			log.Printf("failed to evict post %d of a deleted account: %v", postID, err)
+		}
+	}
+}
+
+// findUser loads a user from the database, failing when there is none
+func (s *UserServiceImpl) findUser(ctx context.Context, userID uint) (*entities.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Unavailable("failed to find user", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// errIncorrectPassword is the error for a wrong password confirming a sensitive change. It is a
+// validation error rather than 401, which would make clients sign the user out.
+func errIncorrectPassword(field string, message string) error {
+	return apperrors.Validation("incorrect_password", message, dto.FieldError{Field: field, Message: "is incorrect"})
+}
+
+// validateProfileURL checks that a profile link is an absolute http(s) URL; an empty value clears it
+func validateProfileURL(field string, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", apperrors.Validation("invalid_profile", field+" must be an http or https URL",
+			dto.FieldError{Field: field, Message: "must be an http or https URL"})
+	}
+	return value, nil
+}
+
+// invalidateUserCache removes the cached copies of a user stored by email and by ID. It follows a saved
+// change, so it runs even if the client has gone away.
+func invalidateUserCache(ctx context.Context, redisService *RedisService, user *entities.User) {
+	ctx, cancel := detach(ctx)
+	defer cancel()
+
+	idStr, _ := helpers.ConvertToString(user.ID)
+	_ = redisService.DeleteEntity(ctx, "user", user.Email)
+	_ = redisService.DeleteEntity(ctx, "user", idStr)
+}
+
 // findUserByEmail checks Redis and database for an existing user
-func (s *UserServiceImpl) findUserByEmail(email string) (*entities.User, error) {
+func (s *UserServiceImpl) findUserByEmail(ctx context.Context, email string) (*entities.User, error) {
 	existingUser := &entities.User{}
-	err := s.redisService.GetEntity("user", email, existingUser)
+	err := s.redisService.GetEntity(ctx, "user", email, existingUser)
 	if err != nil {
-		return nil, errors.New("failed to check existing email in Redis")
+		return nil, apperrors.Unavailable("failed to check existing email in Redis", err)
 	}
 
 	if existingUser.Email == "" {
 		// If not found in Redis, check in the database
-		existingUserFromDB, err := s.userRepo.FindByEmail(email)
+		existingUserFromDB, err := s.userRepo.FindByEmail(ctx, email)
 		if err != nil {
-			return nil, errors.New("failed to check existing email in database")
+			return nil, apperrors.Unavailable("failed to check existing email in database", err)
 		}
 		if existingUserFromDB != nil {
-			// Store user in Redis for future requests
-			err = s.redisService.SetEntity("user", existingUserFromDB.Email, existingUserFromDB, 24*time.Hour)
-			if err != nil {
-				return existingUserFromDB, err
-			}
+			// Store user in Redis for future requests; a cache failure does not fail the lookup
+			_ = s.redisService.SetEntity(ctx, "user", existingUserFromDB.Email, existingUserFromDB, 24*time.Hour)
 		}
-		return nil, nil
+		return existingUserFromDB, nil
 	}
 	return existingUser, nil
 }
@@ -165,7 +466,8 @@ func validateEmail(email string) error {
 	const emailRegex = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	matched, err := regexp.MatchString(emailRegex, email)
 	if err != nil || !matched {
-		return errors.New("invalid email format")
+		return apperrors.Validation("invalid_email", "invalid email format",
+			dto.FieldError{Field: "email", Message: "must be a valid email address"})
 	}
 	return nil
 }